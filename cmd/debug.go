@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ziliscite/dictionary-cli/internal/engine"
+	"os"
+)
+
+// runDebug handles `dict debug states [--dot]`.
+func runDebug(args []string) error {
+	if len(args) == 0 || args[0] != "states" {
+		return fmt.Errorf("usage: dict debug states [--dot]")
+	}
+
+	dot := len(args) > 1 && args[1] == "--dot"
+	return engine.WriteStateGraph(os.Stdout, dot)
+}
@@ -5,6 +5,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/engine"
+	"github.com/ziliscite/dictionary-cli/internal/view"
 	"net/http"
 	"os"
 	"time"
@@ -19,6 +20,34 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	theme, err := view.ResolveTheme(os.Getenv("DICT_THEME"))
+	if err != nil {
+		fmt.Println("Error loading theme:", err)
+		os.Exit(1)
+	}
+	view.ApplyTheme(theme)
+
+	keys, err := engine.LoadKeyMap(keyMapPath())
+	if err != nil {
+		fmt.Println("Error loading key map:", err)
+		os.Exit(1)
+	}
+
+	if err = keys.Validate(); err != nil {
+		fmt.Println("Conflicting key bindings:\n" + err.Error())
+		os.Exit(1)
+	}
+	engine.UseKeyMap(keys)
+
 	htc := &http.Client{
 		Timeout: 120 * time.Second,
 	}
@@ -27,18 +56,24 @@ func main() {
 	loadingModel := engine.NewLoadingModel()
 
 	dictionaryModel := engine.NewDictionaryModel()
-	detailModel := engine.NewDictionaryDetailModel()
 
-	searchModel := engine.NewSearchModel(htc)
+	// the local datasets take a while to parse, they are opened when a screen first needs them
+	kanjidic := domain.NewLazy(func() (*domain.Kanjidic, error) {
+		return domain.OpenKanjidic(kanjidicPath())
+	})
+	examples := domain.NewLazy(func() (*domain.Examples, error) {
+		return domain.OpenExamples(examplesPath(), exampleIndicesPath())
+	})
+	pitch := domain.NewLazy(func() (*domain.PitchAccents, error) {
+		return domain.OpenPitchAccents(pitchPath())
+	})
+	radicals := domain.NewLazy(func() (*domain.Radicals, error) {
+		return domain.OpenRadicals(radkfilePath(), kradfilePath())
+	})
 
-	deepLKey := os.Getenv("DEEPL_KEY")
-	if deepLKey == "" {
-		fmt.Println("DEEPL_KEY is not set")
-		os.Exit(1)
-	}
+	detailModel := engine.NewDictionaryDetailModel(kanjidic, examples, pitch)
 
-	translatorModel := engine.NewTranslatorModel(htc, deepLKey)
-	translateDetailModel := engine.NewTranslationDetailModel()
+	searchModel := engine.NewSearchModel(htc)
 
 	deepSeekKey := os.Getenv("DEEPSEEK_KEY")
 	if deepSeekKey == "" {
@@ -46,9 +81,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	translators := domain.NewTranslatorRegistry()
+	if deepLKey := os.Getenv("DEEPL_KEY"); deepLKey != "" {
+		translators.Register(domain.ProviderDeepL, domain.NewDeepLClient(deepLKey, htc))
+	}
+
+	if libreURL := os.Getenv("LIBRETRANSLATE_URL"); libreURL != "" {
+		translators.Register(domain.ProviderLibreTranslate, domain.NewLibreTranslateClient(libreURL, os.Getenv("LIBRETRANSLATE_KEY"), htc))
+	}
+
+	translators.Register(domain.ProviderLLM, domain.NewLLMTranslator(domain.NewTranslatorChatClient(htc, deepSeekKey, 2888)))
+
+	memory, err := domain.OpenTranslationMemory(memoryPath())
+	if err != nil {
+		fmt.Println("Error loading translation memory:", err)
+		os.Exit(1)
+	}
+
+	translatorModel := engine.NewTranslatorModel(translators, memory)
+	translateDetailModel := engine.NewTranslationDetailModel(translators)
+
 	explainer := domain.NewJapaneseExplainerClient(htc, deepSeekKey, 2888)
 	explainerModel := engine.NewExplainerModel(explainer)
-	explainerDetailModel := engine.NewExplainerDetailModel()
+	explainerDetailModel := engine.NewExplainerDetailModel(domain.NewSearcher(htc))
+
+	compareModel := engine.NewCompareModel(domain.NewReviewerChatClient(htc, deepSeekKey, 2888))
+
+	kanjiModel := engine.NewKanjiModel(radicals, kanjidic)
+	kanjiDetailModel := engine.NewKanjiDetailModel(kanjidic, radicals, domain.NewSearcher(htc))
+
+	lookupModel := engine.NewLookupModel(domain.NewSearcher(htc))
 
 	eng := engine.NewEngine(
 		menuModel,
@@ -60,10 +122,32 @@ func main() {
 		translateDetailModel,
 		explainerModel,
 		explainerDetailModel,
+		compareModel,
+		kanjiModel,
+		kanjiDetailModel,
+		lookupModel,
 	)
 
-	if _, err := tea.NewProgram(eng).Run(); err != nil {
+	if err = eng.Validate(); err != nil {
+		fmt.Println("Invalid UI state machine:", err)
+		os.Exit(1)
+	}
+
+	if _, err := tea.NewProgram(eng, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
 }
+
+func runCommand(name string, args []string) error {
+	switch name {
+	case "tm":
+		return runTM(args)
+	case "debug":
+		return runDebug(args)
+	case "examples":
+		return runExamples(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
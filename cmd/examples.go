@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// examplesPath resolves where the Tatoeba sentence pairs live, DICT_EXAMPLES overrides the default.
+func examplesPath() string {
+	if p := os.Getenv("DICT_EXAMPLES"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "jpn-eng.tsv")
+}
+
+// exampleIndicesPath resolves where Tatoeba's Japanese indices live, DICT_EXAMPLE_INDICES
+// overrides the default.
+func exampleIndicesPath() string {
+	if p := os.Getenv("DICT_EXAMPLE_INDICES"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "jpn_indices.csv")
+}
+
+// runExamples handles `dict examples <word> [count]`.
+func runExamples(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: dict examples <word> [count]")
+	}
+
+	n := 10
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+			return fmt.Errorf("invalid count %q", args[1])
+		}
+	}
+
+	ex, err := domain.OpenExamples(examplesPath(), exampleIndicesPath())
+	if err != nil {
+		return err
+	}
+
+	if ex.Len() == 0 {
+		return fmt.Errorf("no Tatoeba sentence pairs at %s, set DICT_EXAMPLES", examplesPath())
+	}
+
+	found := ex.Find(args[0], n)
+	if len(found) == 0 {
+		return fmt.Errorf("no example sentences for %q", args[0])
+	}
+
+	for _, e := range found {
+		fmt.Printf("%s\n  %s\n", e.Japanese, e.English)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// kanjidicPath resolves where the KANJIDIC2 file lives, DICT_KANJIDIC overrides the default.
+func kanjidicPath() string {
+	if p := os.Getenv("DICT_KANJIDIC"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "kanjidic2.xml.gz")
+}
+
+// radkfilePath resolves where RADKFILE lives, DICT_RADKFILE overrides the default.
+func radkfilePath() string {
+	if p := os.Getenv("DICT_RADKFILE"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "radkfile")
+}
+
+// kradfilePath resolves where KRADFILE lives, DICT_KRADFILE overrides the default.
+func kradfilePath() string {
+	if p := os.Getenv("DICT_KRADFILE"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "kradfile")
+}
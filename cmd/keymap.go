@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// keyMapPath resolves where the key bindings live, DICT_KEYMAP overrides the default.
+func keyMapPath() string {
+	if p := os.Getenv("DICT_KEYMAP"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "keymap.json")
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// pitchPath resolves where the pitch accent list lives, DICT_PITCH overrides the default.
+func pitchPath() string {
+	if p := os.Getenv("DICT_PITCH"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "accents.txt")
+}
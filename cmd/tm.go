@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"os"
+	"path/filepath"
+)
+
+// memoryPath resolves where the translation memory lives, DICT_TM_PATH overrides the default.
+func memoryPath() string {
+	if p := os.Getenv("DICT_TM_PATH"); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "dictionary-cli", "memory.json")
+}
+
+// runTM handles `dict tm import <file.tmx>` and `dict tm export <file.tmx>`.
+func runTM(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: dict tm import|export <file.tmx>")
+	}
+
+	tm, err := domain.OpenTranslationMemory(memoryPath())
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "import":
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		n, err := tm.ImportTMX(f)
+		if err != nil {
+			return err
+		}
+
+		if err = tm.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported %d pairs, %d in memory\n", n, tm.Len())
+		return nil
+
+	case "export":
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err = tm.ExportTMX(f); err != nil {
+			return err
+		}
+
+		fmt.Printf("Exported %d pairs to %s\n", tm.Len(), args[1])
+		return nil
+
+	default:
+		return fmt.Errorf("unknown tm command %q", args[0])
+	}
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// Comparison is the outcome of a single provider translating the compared input.
+type Comparison struct {
+	Provider     string
+	Translations []Translation
+	Latency      time.Duration
+	Characters   int
+	Err          error
+}
+
+// CompareTranslations translates the same texts with every registered provider concurrently.
+// The results keep the registration order of the providers; failures are reported per provider.
+func CompareTranslations(ctx context.Context, registry *TranslatorRegistry, lang TargetLang, texts ...string) []Comparison {
+	var chars int
+	for _, text := range texts {
+		chars += utf8.RuneCountInString(text)
+	}
+
+	names := registry.Names()
+	res := make([]Comparison, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			res[i] = Comparison{Provider: name, Characters: chars}
+
+			tr, err := registry.Get(name)
+			if err != nil {
+				res[i].Err = err
+				return
+			}
+
+			start := time.Now()
+			res[i].Translations, res[i].Err = tr.Translate(ctx, lang, texts...)
+			res[i].Latency = time.Since(start)
+		}(i, name)
+	}
+	wg.Wait()
+
+	return res
+}
+
+type Verdict struct {
+	Best   string `json:"best"`
+	Reason string `json:"reason"`
+}
+
+var verdictPrompt = template.Must(template.New("verdict").Parse(`
+These are translations of the same text into {{.Lang}} made by different translators.
+Decide which one reads the most natural to a native speaker while staying faithful to the original, and output JSON strictly matching the schema.
+
+Original: "{{.Input}}"
+{{range .Candidates}}
+{{.Provider}}: "{{.Text}}"
+{{end}}
+Schema fields required:
+{
+  "best": string (one of the translator names above),
+  "reason": string (1-3 sentences)
+}
+
+Do not include any extra fields.
+`))
+
+// AskMostNatural lets a chat model judge which of the successful comparisons is the most natural.
+func AskMostNatural(ctx context.Context, chat Chatter, lang TargetLang, input string, comparisons []Comparison) (*Verdict, error) {
+	type candidate struct {
+		Provider string
+		Text     string
+	}
+
+	candidates := make([]candidate, 0, len(comparisons))
+	for _, c := range comparisons {
+		if c.Err != nil || len(c.Translations) == 0 {
+			continue
+		}
+
+		var b bytes.Buffer
+		for i, t := range c.Translations {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(t.Text)
+		}
+
+		candidates = append(candidates, candidate{Provider: c.Provider, Text: b.String()})
+	}
+
+	if len(candidates) < 2 {
+		return nil, fmt.Errorf("need at least two translations to compare")
+	}
+
+	var buf bytes.Buffer
+	if err := verdictPrompt.Execute(&buf, map[string]interface{}{
+		"Lang":       lang.String(),
+		"Input":      input,
+		"Candidates": candidates,
+	}); err != nil {
+		return nil, err
+	}
+
+	res, err := chat.Chat(ctx, buf.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var v Verdict
+	if err = json.Unmarshal([]byte(res), &v); err != nil {
+		return nil, fmt.Errorf("decode verdict: %w", err)
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy opens a local dataset the first time it is needed, so startup doesn't wait on files
+// a session may never use. Get blocks while the file is parsed, callers that can't wait check
+// Ready first.
+type Lazy[T any] struct {
+	once  sync.Once
+	ready atomic.Bool
+	open  func() (*T, error)
+	v     *T
+	err   error
+}
+
+func NewLazy[T any](open func() (*T, error)) *Lazy[T] {
+	return &Lazy[T]{open: open}
+}
+
+// Get opens the dataset on the first call and gives the same result after. A dataset that
+// failed to open comes back empty, along with the error.
+func (l *Lazy[T]) Get() (*T, error) {
+	l.once.Do(func() {
+		l.v, l.err = l.open()
+		if l.v == nil {
+			l.v = new(T)
+		}
+		l.ready.Store(true)
+	})
+
+	return l.v, l.err
+}
+
+// Ready reports whether the dataset is open, Get then returns without waiting.
+func (l *Lazy[T]) Ready() bool {
+	return l.ready.Load()
+}
+
+// openDataset opens a local data file, transparently decompressing it when its name ends in .gz.
+func openDataset(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return gzipFile{zr, f}, nil
+}
+
+// gzipFile closes both the decompressor and the file under it.
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
@@ -95,6 +95,24 @@ func NewJapaneseExplainerClient(client *http.Client, apiKey string, maxTokens in
 	return NewDeepSeekClient(client, apiKey, deepSeekModel, "json_object", maxTokens, 0.1, false, explainerSystemPrompt)
 }
 
+const translatorSystemPrompt = `
+You are a professional translator. Translate the text you are given faithfully and naturally, keeping the tone, register and formatting of the original.
+Never add commentary, notes or alternatives. Return output in JSON following the schema provided.
+`
+
+func NewTranslatorChatClient(client *http.Client, apiKey string, maxTokens int) ChatBot {
+	return NewDeepSeekClient(client, apiKey, deepSeekModel, "json_object", maxTokens, 0.2, false, translatorSystemPrompt)
+}
+
+const reviewerSystemPrompt = `
+You are a careful, impartial reviewer of translations, fluent in Japanese, English and Indonesian.
+Judge the candidates you are given on the criteria asked for only, in the language of the question. Return output in JSON following the schema provided.
+`
+
+func NewReviewerChatClient(client *http.Client, apiKey string, maxTokens int) ChatBot {
+	return NewDeepSeekClient(client, apiKey, deepSeekModel, "json_object", maxTokens, 0.1, false, reviewerSystemPrompt)
+}
+
 func (c *chatClient) SetSystemPrompt(prompt string) {
 	c.systemPrompt = prompt
 }
@@ -264,29 +282,33 @@ func (c *chatClient) validateJapanese(content string) string {
 	return valid
 }
 
-func (c *chatClient) Ask(ctx context.Context, content string) (*Explanation, error) {
-	japanese := c.validateJapanese(content)
-	if japanese == "" {
-		return nil, fmt.Errorf("invalid japanese sentence")
+func (c *chatClient) Chat(ctx context.Context, content string) (string, error) {
+	body, err := c.request(content)
+	if err != nil {
+		return "", err
 	}
 
-	exp, err := c.buildExplainPrompt(japanese)
+	res, err := c.execute(ctx, body, "/completions")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	defer res.Body.Close()
 
-	body, err := c.request(exp)
-	if err != nil {
-		return nil, err
+	return c.handleResponse(res)
+}
+
+func (c *chatClient) Ask(ctx context.Context, content string) (*Explanation, error) {
+	japanese := c.validateJapanese(content)
+	if japanese == "" {
+		return nil, fmt.Errorf("invalid japanese sentence")
 	}
 
-	res, err := c.execute(ctx, body, "/completions")
+	exp, err := c.buildExplainPrompt(japanese)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	stringRes, err := c.handleResponse(res)
+	stringRes, err := c.Chat(ctx, exp)
 	if err != nil {
 		return nil, err
 	}
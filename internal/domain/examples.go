@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// rareCount is how few sentences of the corpus a word (or, without the headword index, a
+// character) must appear in to count as rare, each rare one makes a sentence a worse example.
+const (
+	rareCount   = 50
+	rarePenalty = 4
+)
+
+// Example is a Japanese sentence of the Tatoeba corpus with its English translation. Form is
+// how the word looked up is written in the sentence, conjugated or not, when it is known.
+type Example struct {
+	Japanese string
+	English  string
+	Form     string
+}
+
+type example struct {
+	Example
+	score int // lower is a better example, see Examples.rank
+}
+
+// wordUse is a sentence using a headword, and the form it takes there.
+type wordUse struct {
+	sentence int32
+	form     string
+}
+
+// Examples are the Japanese–English sentence pairs of a local Tatoeba export. With Tatoeba's
+// Japanese indices they are indexed by the dictionary form of their words, otherwise by the
+// characters of the sentences.
+type Examples struct {
+	sentences []example
+	words     map[string][]wordUse // headword to the sentences using it, in order
+	index     map[rune][]int32     // character to the sentences using it, in order
+}
+
+// OpenExamples loads the Tatoeba "sentence pairs" TSV at path: Japanese id, Japanese text,
+// English id, English text. Sentences with several translations keep the first one. The
+// "Japanese indices" at indicesPath map the sentences to the headwords they use, so that
+// conjugated forms are found too. Missing files give an empty store, or no headword index.
+func OpenExamples(path, indicesPath string) (*Examples, error) {
+	ex := &Examples{
+		words: make(map[string][]wordUse),
+		index: make(map[rune][]int32),
+	}
+
+	f, err := openDataset(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ex, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open examples: %w", err)
+	}
+	defer f.Close()
+
+	ids := make(map[string]int32)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) < 4 {
+			continue
+		}
+
+		id, ja, en := fields[0], strings.TrimSpace(fields[1]), strings.TrimSpace(fields[3])
+		if _, seen := ids[id]; seen || ja == "" || en == "" {
+			continue
+		}
+
+		n := int32(len(ex.sentences))
+		ids[id] = n
+		ex.sentences = append(ex.sentences, example{Example: Example{Japanese: ja, English: en}})
+		for _, r := range distinctRunes(ja) {
+			ex.index[r] = append(ex.index[r], n)
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("read examples: %w", err)
+	}
+
+	heads, err := ex.readIndices(indicesPath, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ex.rank(heads)
+	return ex, nil
+}
+
+// readIndices indexes the sentences by the headwords of Tatoeba's Japanese indices: Japanese id,
+// English id and the words of the sentence. It returns the headwords of every sentence.
+func (ex *Examples) readIndices(path string, ids map[string]int32) ([][]string, error) {
+	f, err := openDataset(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open example indices: %w", err)
+	}
+	defer f.Close()
+
+	heads := make([][]string, len(ex.sentences))
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) < 3 {
+			continue
+		}
+
+		n, ok := ids[fields[0]]
+		if !ok || heads[n] != nil {
+			continue
+		}
+
+		for _, w := range strings.Fields(fields[2]) {
+			head, form := parseIndexWord(w)
+			if head == "" || slices.Contains(heads[n], head) {
+				continue
+			}
+
+			heads[n] = append(heads[n], head)
+			ex.words[head] = append(ex.words[head], wordUse{sentence: n, form: form})
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("read example indices: %w", err)
+	}
+
+	return heads, nil
+}
+
+// parseIndexWord splits a word of the indices, written headword(reading)[sense]{form}~ with
+// everything but the headword optional, into its headword and the form used in the sentence.
+func parseIndexWord(w string) (head, form string) {
+	head = w
+	if i := strings.IndexAny(w, "([{~|"); i >= 0 {
+		head = w[:i]
+	}
+
+	form = head
+	if _, rest, ok := strings.Cut(w, "{"); ok {
+		if f, _, ok := strings.Cut(rest, "}"); ok && f != "" {
+			form = f
+		}
+	}
+
+	return head, form
+}
+
+// rank scores every sentence by its length, plus a penalty for each word (each character without
+// the headword index) few other sentences use, so short sentences of common vocabulary come first.
+func (ex *Examples) rank(heads [][]string) {
+	for i := range ex.sentences {
+		s := &ex.sentences[i]
+		s.score = utf8.RuneCountInString(s.Japanese)
+
+		if len(ex.words) > 0 {
+			for _, h := range heads[i] {
+				if len(ex.words[h]) < rareCount {
+					s.score += rarePenalty
+				}
+			}
+			continue
+		}
+
+		for _, r := range distinctRunes(s.Japanese) {
+			if len(ex.index[r]) < rareCount {
+				s.score += rarePenalty
+			}
+		}
+	}
+}
+
+func distinctRunes(s string) []rune {
+	var rs []rune
+	for _, r := range s {
+		if !slices.Contains(rs, r) {
+			rs = append(rs, r)
+		}
+	}
+
+	return rs
+}
+
+func (ex *Examples) Len() int {
+	return len(ex.sentences)
+}
+
+// Find returns up to n sentences using the word, the best examples first. With the headword
+// index the word is matched in any of its forms, otherwise only as written.
+func (ex *Examples) Find(word string, n int) []Example {
+	word = strings.TrimSpace(word)
+	if word == "" || n <= 0 {
+		return nil
+	}
+
+	var found []example
+	if len(ex.words) > 0 {
+		for _, u := range ex.words[word] {
+			s := ex.sentences[u.sentence]
+			s.Form = u.form
+			found = append(found, s)
+		}
+	} else {
+		found = ex.containing(word)
+	}
+
+	slices.SortStableFunc(found, func(a, b example) int {
+		return a.score - b.score
+	})
+
+	res := make([]Example, 0, min(n, len(found)))
+	for _, s := range found[:min(n, len(found))] {
+		res = append(res, s.Example)
+	}
+
+	return res
+}
+
+// containing are the sentences with the word written in them.
+func (ex *Examples) containing(word string) []example {
+	// only the sentences listed under the word's least used character can contain it
+	var postings []int32
+	for i, r := range distinctRunes(word) {
+		if p := ex.index[r]; i == 0 || len(p) < len(postings) {
+			postings = p
+		}
+	}
+
+	var found []example
+	for _, i := range postings {
+		if s := ex.sentences[i]; strings.Contains(s.Japanese, word) {
+			s.Form = word
+			found = append(found, s)
+		}
+	}
+
+	return found
+}
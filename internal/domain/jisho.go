@@ -20,6 +20,10 @@ type Information struct {
 	Senses []struct {
 		EnglishDefinitions []string `json:"english_definitions"`
 		PartsOfSpeech      []string `json:"parts_of_speech"`
+		Tags               []string `json:"tags"`
+		Info               []string `json:"info"`
+		SeeAlso            []string `json:"see_also"`
+		Antonyms           []string `json:"antonyms"`
 	} `json:"senses"`
 }
 
@@ -89,3 +93,20 @@ func (s *searcher) Search(keyword string) ([]Information, error) {
 
 	return jisho.Data, nil
 }
+
+// BestMatch picks the result written or, for kana-only words, read exactly as word, the first one otherwise.
+func BestMatch(res []Information, word string) *Information {
+	if len(res) == 0 {
+		return nil
+	}
+
+	for i := range res {
+		for _, j := range res[i].Japanese {
+			if j.Word == word || (j.Word == "" && j.Reading == word) {
+				return &res[i]
+			}
+		}
+	}
+
+	return &res[0]
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kanji is a character of KANJIDIC2, zero numbers are unknown.
+type Kanji struct {
+	Literal  rune
+	Meanings []string // English only
+	On       []string
+	Kun      []string
+	Nanori   []string
+	Strokes  int
+	Grade    int // 1-6 kyōiku, 8 jōyō, 9-10 jinmeiyō
+	JLPT     int // the pre-2010 levels, 4 is the easiest
+	Freq     int // rank among the 2,500 most used kanji in newspapers
+	Radical  int // classical Kangxi radical number
+}
+
+// Kanjidic holds the characters of a local KANJIDIC2 file.
+type Kanjidic struct {
+	chars map[rune]Kanji
+}
+
+// kanjidicCharacter is a <character> element of KANJIDIC2, only the fields we use.
+type kanjidicCharacter struct {
+	Literal  string `xml:"literal"`
+	Radicals []struct {
+		Type  string `xml:"rad_type,attr"`
+		Value int    `xml:",chardata"`
+	} `xml:"radical>rad_value"`
+	Misc struct {
+		Grade   int   `xml:"grade"`
+		Strokes []int `xml:"stroke_count"`
+		Freq    int   `xml:"freq"`
+		JLPT    int   `xml:"jlpt"`
+	} `xml:"misc"`
+	Readings []struct {
+		Type  string `xml:"r_type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>reading"`
+	Meanings []struct {
+		Lang  string `xml:"m_lang,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>meaning"`
+	Nanori []string `xml:"reading_meaning>nanori"`
+}
+
+// OpenKanjidic loads the KANJIDIC2 XML at path, gzipped or not; a missing file gives an empty dictionary.
+func OpenKanjidic(path string) (*Kanjidic, error) {
+	kd := &Kanjidic{
+		chars: make(map[rune]Kanji),
+	}
+
+	f, err := openDataset(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return kd, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open kanjidic: %w", err)
+	}
+	defer f.Close()
+
+	d := xml.NewDecoder(f)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode kanjidic: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "character" {
+			continue
+		}
+
+		var c kanjidicCharacter
+		if err = d.DecodeElement(&c, &start); err != nil {
+			return nil, fmt.Errorf("decode kanjidic: %w", err)
+		}
+
+		if k, ok := c.kanji(); ok {
+			kd.chars[k.Literal] = k
+		}
+	}
+
+	return kd, nil
+}
+
+func (c kanjidicCharacter) kanji() (Kanji, bool) {
+	r, size := utf8.DecodeRuneInString(c.Literal)
+	if r == utf8.RuneError || size != len(c.Literal) {
+		return Kanji{}, false
+	}
+
+	k := Kanji{
+		Literal: r,
+		Nanori:  c.Nanori,
+		Grade:   c.Misc.Grade,
+		JLPT:    c.Misc.JLPT,
+		Freq:    c.Misc.Freq,
+	}
+
+	// the first stroke count is the accepted one, the others are common miscounts
+	if len(c.Misc.Strokes) > 0 {
+		k.Strokes = c.Misc.Strokes[0]
+	}
+
+	for _, rad := range c.Radicals {
+		if rad.Type == "classical" {
+			k.Radical = rad.Value
+		}
+	}
+
+	for _, r := range c.Readings {
+		switch r.Type {
+		case "ja_on":
+			k.On = append(k.On, r.Value)
+		case "ja_kun":
+			k.Kun = append(k.Kun, r.Value)
+		}
+	}
+
+	for _, m := range c.Meanings {
+		if m.Lang == "" || m.Lang == "en" {
+			k.Meanings = append(k.Meanings, strings.TrimSpace(m.Value))
+		}
+	}
+
+	return k, true
+}
+
+func (kd *Kanjidic) Len() int {
+	return len(kd.chars)
+}
+
+// Lookup finds a character, false when the file doesn't list it.
+func (kd *Kanjidic) Lookup(r rune) (Kanji, bool) {
+	k, ok := kd.chars[r]
+	return k, ok
+}
+
+// Sort orders kanji by stroke count, then the most frequent first. Unknown kanji go last.
+func (kd *Kanjidic) Sort(kanji []rune) {
+	rank := func(r rune) (int, int) {
+		k, ok := kd.chars[r]
+		if !ok {
+			return math.MaxInt, math.MaxInt
+		}
+
+		strokes, freq := k.Strokes, k.Freq
+		if strokes == 0 {
+			strokes = math.MaxInt
+		}
+		if freq == 0 {
+			freq = math.MaxInt
+		}
+
+		return strokes, freq
+	}
+
+	slices.SortStableFunc(kanji, func(a, b rune) int {
+		as, af := rank(a)
+		bs, bf := rank(b)
+		if as != bs {
+			return cmp.Compare(as, bs)
+		}
+
+		return cmp.Compare(af, bf)
+	})
+}
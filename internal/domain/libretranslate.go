@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type libreRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type libreResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage struct {
+		Confidence float64 `json:"confidence"`
+		Language   string  `json:"language"`
+	} `json:"detectedLanguage"`
+}
+
+type libreTranslator struct {
+	client *http.Client
+
+	base string
+	key  string
+}
+
+// NewLibreTranslateClient talks to a (possibly self-hosted) LibreTranslate instance.
+// The key is optional, most self-hosted instances don't require one.
+func NewLibreTranslateClient(baseURL, apiKey string, client *http.Client) Translator {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &libreTranslator{
+		client: client,
+		base:   strings.TrimRight(baseURL, "/"),
+		key:    apiKey,
+	}
+}
+
+func (t *libreTranslator) request(lang TargetLang, text string) (io.Reader, error) {
+	if text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	b, err := json.Marshal(libreRequest{
+		Q:      text,
+		Source: "auto",
+		Target: strings.ToLower(lang.Code()),
+		Format: "text",
+		APIKey: t.key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	return bytes.NewReader(b), nil
+}
+
+func (t *libreTranslator) execute(ctx context.Context, body io.Reader, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.base+endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		return nil, fmt.Errorf("libretranslate returned status %d: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	return res, nil
+}
+
+func (t *libreTranslator) translate(ctx context.Context, lang TargetLang, text string) (*Translation, error) {
+	body, err := t.request(lang, text)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.execute(ctx, body, "/translate")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var libre libreResponse
+	if err = json.NewDecoder(res.Body).Decode(&libre); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &Translation{
+		DetectedSourceLanguage: strings.ToUpper(libre.DetectedLanguage.Language),
+		Text:                   libre.TranslatedText,
+	}, nil
+}
+
+// Translate sends one request per text, since LibreTranslate only reports
+// the detected language for single inputs.
+func (t *libreTranslator) Translate(ctx context.Context, lang TargetLang, texts ...string) ([]Translation, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	translations := make([]Translation, 0, len(texts))
+	for _, text := range texts {
+		tr, err := t.translate(ctx, lang, text)
+		if err != nil {
+			return nil, err
+		}
+
+		translations = append(translations, *tr)
+	}
+
+	return translations, nil
+}
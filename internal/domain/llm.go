@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var translatePrompt = template.Must(template.New("translate").Parse(`
+Translate the following text into {{.Lang}} and output JSON strictly matching the schema.
+
+Text: "{{.Input}}"
+
+Schema fields required:
+{
+  "detected_source_language": string (two letter uppercase ISO 639-1 code, e.g. "EN"),
+  "text": string
+}
+
+Do not include any extra fields.
+`))
+
+type llmTranslator struct {
+	chat Chatter
+}
+
+// NewLLMTranslator translates through a chat model. The chat client is expected
+// to answer in JSON, see NewTranslatorChatClient.
+func NewLLMTranslator(chat Chatter) Translator {
+	return &llmTranslator{
+		chat: chat,
+	}
+}
+
+func (t *llmTranslator) buildPrompt(lang TargetLang, input string) (string, error) {
+	var buf bytes.Buffer
+	if err := translatePrompt.Execute(&buf, map[string]interface{}{
+		"Lang":  lang.String(),
+		"Input": input,
+	}); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (t *llmTranslator) translate(ctx context.Context, lang TargetLang, text string) (*Translation, error) {
+	prompt, err := t.buildPrompt(lang, text)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.chat.Chat(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr Translation
+	if err = json.Unmarshal([]byte(res), &tr); err != nil {
+		return nil, fmt.Errorf("decode translation: %w", err)
+	}
+
+	tr.DetectedSourceLanguage = strings.ToUpper(tr.DetectedSourceLanguage)
+	return &tr, nil
+}
+
+func (t *llmTranslator) Translate(ctx context.Context, lang TargetLang, texts ...string) ([]Translation, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	translations := make([]Translation, 0, len(texts))
+	for _, text := range texts {
+		tr, err := t.translate(ctx, lang, text)
+		if err != nil {
+			return nil, err
+		}
+
+		translations = append(translations, *tr)
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,403 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryEntry is a single source/target pair recorded in the translation memory.
+type MemoryEntry struct {
+	Source     string    `json:"source"`
+	SourceLang string    `json:"source_lang"`
+	Target     string    `json:"target"`
+	TargetLang string    `json:"target_lang"`
+	Created    time.Time `json:"created"`
+}
+
+type MemoryMatch struct {
+	Entry MemoryEntry
+	Score float64
+}
+
+func (m MemoryMatch) Exact() bool {
+	return m.Score == 1
+}
+
+func (m MemoryMatch) Percent() int {
+	return int(m.Score * 100)
+}
+
+// TranslationMemory is a local store of every translated pair, persisted as JSON.
+type TranslationMemory struct {
+	mu sync.RWMutex
+	// saveMu keeps saves from racing each other on the file.
+	saveMu sync.Mutex
+
+	path    string
+	entries []MemoryEntry
+}
+
+// OpenTranslationMemory loads the memory stored at path; a missing file starts an empty memory.
+func OpenTranslationMemory(path string) (*TranslationMemory, error) {
+	tm := &TranslationMemory{
+		path:    path,
+		entries: make([]MemoryEntry, 0),
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tm, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open translation memory: %w", err)
+	}
+	defer f.Close()
+
+	if err = json.NewDecoder(f).Decode(&tm.entries); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode translation memory: %w", err)
+	}
+
+	return tm, nil
+}
+
+// Path is where the memory is saved.
+func (m *TranslationMemory) Path() string {
+	return m.path
+}
+
+func (m *TranslationMemory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.entries)
+}
+
+// Add records a pair, replacing the previous target of the same source and target language.
+func (m *TranslationMemory) Add(entry MemoryEntry) {
+	if strings.TrimSpace(entry.Source) == "" || strings.TrimSpace(entry.Target) == "" {
+		return
+	}
+
+	if entry.Created.IsZero() {
+		entry.Created = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.entries {
+		if e.Source == entry.Source && e.TargetLang == entry.TargetLang {
+			m.entries[i] = entry
+			return
+		}
+	}
+
+	m.entries = append(m.entries, entry)
+}
+
+// Save writes the memory to a temporary file next to its path and moves it into place, so a
+// crash or a concurrent save never leaves a half-written file.
+func (m *TranslationMemory) Save() error {
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+
+	m.mu.RLock()
+	b, err := json.MarshalIndent(m.entries, "", "  ")
+	m.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("marshal translation memory: %w", err)
+	}
+
+	dir := filepath.Dir(m.path)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create translation memory dir: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save translation memory: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("save translation memory: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("save translation memory: %w", err)
+	}
+
+	if err = os.Chmod(f.Name(), 0o644); err != nil {
+		return fmt.Errorf("save translation memory: %w", err)
+	}
+
+	if err = os.Rename(f.Name(), m.path); err != nil {
+		return fmt.Errorf("save translation memory: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the pairs translated into lang whose source is at least threshold similar,
+// best match first. An exact match always scores 1. Sources that can't reach the threshold are
+// skipped before their edit distance is computed.
+func (m *TranslationMemory) Lookup(source string, lang TargetLang, threshold float64, limit int) []MemoryMatch {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil
+	}
+
+	norm := normalize(source)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matches := make([]MemoryMatch, 0)
+	for _, e := range m.entries {
+		if e.TargetLang != lang.Code() {
+			continue
+		}
+
+		score := 1.0
+		if e.Source != source {
+			other := normalize(e.Source)
+			if similarityBound(norm, other) < threshold {
+				continue
+			}
+
+			score = min(similarity(norm, other), 0.99)
+		}
+
+		if score >= threshold {
+			matches = append(matches, MemoryMatch{Entry: e, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+type tmx struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	SrcLang      string       `xml:"srclang,attr,omitempty"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+// source splits the unit into its source variant, the one in srclang or the first one when
+// srclang is *all* or unset, and the others. ok is false when no variant is in srclang.
+func (u tmxUnit) source(srclang string) (src tmxVariant, others []tmxVariant, ok bool) {
+	if u.SrcLang != "" {
+		srclang = u.SrcLang
+	}
+
+	i := 0
+	if srclang != "" && srclang != "*all*" {
+		i = slices.IndexFunc(u.Variants, func(v tmxVariant) bool {
+			return sameLang(v.Lang, srclang)
+		})
+		if i < 0 {
+			return tmxVariant{}, nil, false
+		}
+	}
+
+	others = append(slices.Clone(u.Variants[:i]), u.Variants[i+1:]...)
+	return u.Variants[i], others, true
+}
+
+// sameLang compares language tags by their primary language, so "en" matches "EN-US".
+func sameLang(a, b string) bool {
+	a, _, _ = strings.Cut(a, "-")
+	b, _, _ = strings.Cut(b, "-")
+	return strings.EqualFold(a, b)
+}
+
+type tmxVariant struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Seg  string `xml:"seg"`
+}
+
+const tmxDate = "20060102T150405Z"
+
+// ExportTMX writes the memory as a TMX 1.4 document, one translation unit per pair.
+func (m *TranslationMemory) ExportTMX(w io.Writer) error {
+	m.mu.RLock()
+	doc := tmx{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "dictionary-cli",
+			CreationToolVersion: "1",
+			SegType:             "sentence",
+			OTMF:                "json",
+			AdminLang:           "en",
+			SrcLang:             "*all*",
+			DataType:            "plaintext",
+		},
+		Units: make([]tmxUnit, 0, len(m.entries)),
+	}
+
+	for _, e := range m.entries {
+		doc.Units = append(doc.Units, tmxUnit{
+			CreationDate: e.Created.UTC().Format(tmxDate),
+			SrcLang:      e.SourceLang,
+			Variants: []tmxVariant{
+				{Lang: e.SourceLang, Seg: e.Source},
+				{Lang: e.TargetLang, Seg: e.Target},
+			},
+		})
+	}
+	m.mu.RUnlock()
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode tmx: %w", err)
+	}
+
+	return nil
+}
+
+// ImportTMX adds every unit of a TMX document. The variant in the source language of the unit,
+// or of the header, is taken as the source (the first variant when it is *all*), every other
+// variant in a supported language becomes a pair. It returns the number of pairs added.
+func (m *TranslationMemory) ImportTMX(r io.Reader) (int, error) {
+	var doc tmx
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("decode tmx: %w", err)
+	}
+
+	var n int
+	for _, u := range doc.Units {
+		if len(u.Variants) < 2 {
+			continue
+		}
+
+		src, targets, ok := u.source(doc.Header.SrcLang)
+		if !ok {
+			continue
+		}
+
+		created, _ := time.Parse(tmxDate, u.CreationDate)
+		for _, v := range targets {
+			lang, err := ParseTargetLang(v.Lang)
+			if err != nil {
+				continue
+			}
+
+			m.Add(MemoryEntry{
+				Source:     src.Seg,
+				SourceLang: strings.ToUpper(src.Lang),
+				Target:     v.Seg,
+				TargetLang: lang.Code(),
+				Created:    created,
+			})
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+type memoryTranslator struct {
+	next   Translator
+	memory *TranslationMemory
+}
+
+// NewMemoryTranslator answers exact matches from the memory and records every other pair
+// the wrapped translator returns. It doesn't save the memory, the caller does once it is done.
+func NewMemoryTranslator(t Translator, memory *TranslationMemory) Translator {
+	return &memoryTranslator{
+		next:   t,
+		memory: memory,
+	}
+}
+
+func (t *memoryTranslator) Translate(ctx context.Context, lang TargetLang, texts ...string) ([]Translation, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	translations := make([]Translation, len(texts))
+	misses := make([]int, 0, len(texts))
+	for i, text := range texts {
+		matches := t.memory.Lookup(text, lang, 1, 1)
+		if len(matches) == 0 || matches[0].Entry.Source != strings.TrimSpace(text) {
+			misses = append(misses, i)
+			continue
+		}
+
+		translations[i] = Translation{
+			DetectedSourceLanguage: matches[0].Entry.SourceLang,
+			Text:                   matches[0].Entry.Target,
+		}
+	}
+
+	if len(misses) == 0 {
+		return translations, nil
+	}
+
+	pending := make([]string, len(misses))
+	for i, idx := range misses {
+		pending[i] = texts[idx]
+	}
+
+	res, err := t.next.Translate(ctx, lang, pending...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) != len(pending) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(pending), len(res))
+	}
+
+	for i, idx := range misses {
+		translations[idx] = res[i]
+		t.memory.Add(MemoryEntry{
+			Source:     strings.TrimSpace(texts[idx]),
+			SourceLang: res[i].DetectedSourceLanguage,
+			Target:     res[i].Text,
+			TargetLang: lang.Code(),
+		})
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMemory(t *testing.T, entries ...MemoryEntry) *TranslationMemory {
+	t.Helper()
+
+	m, err := OpenTranslationMemory(filepath.Join(t.TempDir(), "memory.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		m.Add(e)
+	}
+
+	return m
+}
+
+func TestTranslationMemoryLookup(t *testing.T) {
+	m := testMemory(t,
+		MemoryEntry{Source: "I eat sushi every day.", SourceLang: "EN", Target: "毎日寿司を食べる。", TargetLang: "JA"},
+		MemoryEntry{Source: "I eat sushi every night.", SourceLang: "EN", Target: "毎晩寿司を食べる。", TargetLang: "JA"},
+		MemoryEntry{Source: "The weather is nice.", SourceLang: "EN", Target: "いい天気だ。", TargetLang: "JA"},
+		MemoryEntry{Source: "I eat sushi every day.", SourceLang: "EN", Target: "Saya makan sushi setiap hari.", TargetLang: "ID"},
+	)
+
+	tests := []struct {
+		name      string
+		source    string
+		lang      TargetLang
+		threshold float64
+		limit     int
+		want      []string
+		exact     bool
+	}{
+		{"exact match first", "I eat sushi every day.", TargetJapanese, 0.7, 0, []string{"毎日寿司を食べる。", "毎晩寿司を食べる。"}, true},
+		{"threshold drops the farther match", "I eat sushi every day.", TargetJapanese, 0.8, 0, []string{"毎日寿司を食べる。"}, true},
+		{"normalized match is not exact", "i eat sushi every day", TargetJapanese, 0.8, 0, []string{"毎日寿司を食べる。"}, false},
+		{"threshold 1 takes exact matches only", "i eat sushi every day", TargetJapanese, 1, 0, nil, false},
+		{"limit", "I eat sushi every day.", TargetJapanese, 0.5, 1, []string{"毎日寿司を食べる。"}, true},
+		{"other target language", "I eat sushi every day.", TargetIndonesia, 0.5, 0, []string{"Saya makan sushi setiap hari."}, true},
+		{"nothing close", "Where is the station?", TargetJapanese, 0.7, 0, nil, false},
+		{"blank source", "   ", TargetJapanese, 0, 0, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := m.Lookup(tt.source, tt.lang, tt.threshold, tt.limit)
+
+			var got []string
+			for _, mm := range matches {
+				got = append(got, mm.Entry.Target)
+				if mm.Score < tt.threshold {
+					t.Errorf("%q scored %v, below the threshold %v", mm.Entry.Source, mm.Score, tt.threshold)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Lookup(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+
+			if len(matches) > 0 && matches[0].Exact() != tt.exact {
+				t.Errorf("first match exact = %v, want %v", matches[0].Exact(), tt.exact)
+			}
+		})
+	}
+}
+
+func TestTranslationMemoryTMXRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	entries := []MemoryEntry{
+		{Source: "I eat sushi.", SourceLang: "EN", Target: "寿司を食べる。", TargetLang: "JA", Created: created},
+		{Source: "寿司を食べる。", SourceLang: "JA", Target: "Saya makan sushi.", TargetLang: "ID", Created: created},
+		{Source: "Fish & chips <3", SourceLang: "EN", Target: "フィッシュ＆チップス", TargetLang: "JA", Created: created},
+	}
+
+	var buf bytes.Buffer
+	if err := testMemory(t, entries...).ExportTMX(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := testMemory(t)
+	n, err := imported.ImportTMX(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(entries) {
+		t.Fatalf("imported %d pairs, want %d", n, len(entries))
+	}
+
+	for _, e := range entries {
+		lang, _ := ParseTargetLang(e.TargetLang)
+		matches := imported.Lookup(e.Source, lang, 1, 0)
+		if len(matches) != 1 {
+			t.Fatalf("Lookup(%q) found %d matches, want 1", e.Source, len(matches))
+		}
+
+		if got := matches[0].Entry; got != e {
+			t.Errorf("round trip = %+v, want %+v", got, e)
+		}
+	}
+}
+
+func TestTranslationMemoryImportTMXSourceLang(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header srclang="en-US" creationtool="test" creationtoolversion="1" segtype="sentence" o-tmf="test" adminlang="en" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="ja"><seg>こんにちは</seg></tuv>
+      <tuv xml:lang="EN-US"><seg>Hello</seg></tuv>
+    </tu>
+    <tu srclang="ja">
+      <tuv xml:lang="en"><seg>Thank you</seg></tuv>
+      <tuv xml:lang="ja"><seg>ありがとう</seg></tuv>
+    </tu>
+    <tu>
+      <tuv xml:lang="ja"><seg>さようなら</seg></tuv>
+      <tuv xml:lang="id"><seg>Selamat tinggal</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+	m := testMemory(t)
+	n, err := m.ImportTMX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 {
+		t.Fatalf("imported %d pairs, want 2: the unit without a header srclang variant is skipped", n)
+	}
+
+	tests := []struct {
+		source     string
+		sourceLang string
+		lang       TargetLang
+		target     string
+	}{
+		{"Hello", "EN-US", TargetJapanese, "こんにちは"},
+		{"ありがとう", "JA", TargetEnglish, "Thank you"},
+	}
+
+	for _, tt := range tests {
+		matches := m.Lookup(tt.source, tt.lang, 1, 0)
+		if len(matches) != 1 {
+			t.Fatalf("Lookup(%q) found %d matches, want 1", tt.source, len(matches))
+		}
+
+		if e := matches[0].Entry; e.SourceLang != tt.sourceLang || e.Target != tt.target {
+			t.Errorf("Lookup(%q) = %s %q, want %s %q", tt.source, e.SourceLang, e.Target, tt.sourceLang, tt.target)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PitchPattern names an accent by where the pitch drops.
+type PitchPattern int
+
+const (
+	Heiban    PitchPattern = iota // no drop, flat after the first mora
+	Atamadaka                     // drop after the first mora
+	Nakadaka                      // drop inside the word
+	Odaka                         // drop after the last mora, heard on a following particle
+)
+
+func (p PitchPattern) String() string {
+	if p < Heiban || p > Odaka {
+		return "unknown"
+	}
+
+	return [...]string{"heiban", "atamadaka", "nakadaka", "odaka"}[p]
+}
+
+// PitchAccent is the accent of a reading, Downstep is the mora the pitch drops after, 0 when
+// it doesn't.
+type PitchAccent struct {
+	Reading  string
+	Downstep int
+}
+
+func (a PitchAccent) Pattern() PitchPattern {
+	switch n := len(Morae(a.Reading)); {
+	case a.Downstep == 0:
+		return Heiban
+	case a.Downstep == 1:
+		return Atamadaka
+	case a.Downstep >= n:
+		return Odaka
+	default:
+		return Nakadaka
+	}
+}
+
+// High reports whether mora i of the reading is said high.
+func (a PitchAccent) High(i int) bool {
+	switch a.Downstep {
+	case 0:
+		return i > 0
+	case 1:
+		return i == 0
+	default:
+		return i > 0 && i < a.Downstep
+	}
+}
+
+// Morae splits kana into morae, small kana belong to the mora before them.
+func Morae(kana string) []string {
+	var morae []string
+	for _, r := range kana {
+		if strings.ContainsRune("ゃゅょぁぃぅぇぉゎャュョァィゥェォヮ", r) && len(morae) > 0 {
+			morae[len(morae)-1] += string(r)
+			continue
+		}
+
+		morae = append(morae, string(r))
+	}
+
+	return morae
+}
+
+// PitchAccents maps words and their readings to their accents, from a local accent list.
+type PitchAccents struct {
+	accents map[string][]int
+}
+
+func pitchKey(word, reading string) string {
+	return word + "\t" + reading
+}
+
+// OpenPitchAccents loads a tab-separated accent list at path: word, reading (empty when the word
+// is kana) and comma-separated downsteps, as in the Kanjium accents.txt. Annotations such as
+// "(名)" before a number are ignored. A missing file gives an empty list.
+func OpenPitchAccents(path string) (*PitchAccents, error) {
+	pa := &PitchAccents{
+		accents: make(map[string][]int),
+	}
+
+	f, err := openDataset(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pa, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open pitch accents: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) < 3 || fields[0] == "" {
+			continue
+		}
+
+		word, reading := fields[0], fields[1]
+		if reading == "" {
+			reading = word
+		}
+
+		var downsteps []int
+		for _, d := range strings.Split(fields[2], ",") {
+			if _, num, ok := strings.Cut(d, ")"); ok {
+				d = num
+			}
+
+			if n, err := strconv.Atoi(strings.TrimSpace(d)); err == nil {
+				downsteps = append(downsteps, n)
+			}
+		}
+
+		if len(downsteps) > 0 {
+			pa.accents[pitchKey(word, reading)] = downsteps
+		}
+	}
+
+	if err = sc.Err(); err != nil {
+		return nil, fmt.Errorf("read pitch accents: %w", err)
+	}
+
+	return pa, nil
+}
+
+func (pa *PitchAccents) Len() int {
+	return len(pa.accents)
+}
+
+// Lookup finds the accents of a written form and its reading, the most common first.
+// Kana-only words have no written form.
+func (pa *PitchAccents) Lookup(word, reading string) []PitchAccent {
+	if word == "" {
+		word = reading
+	}
+
+	var accents []PitchAccent
+	for _, d := range pa.accents[pitchKey(word, reading)] {
+		accents = append(accents, PitchAccent{Reading: reading, Downstep: d})
+	}
+
+	return accents
+}
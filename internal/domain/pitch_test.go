@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMorae(t *testing.T) {
+	tests := []struct {
+		kana string
+		want []string
+	}{
+		{"", nil},
+		{"はし", []string{"は", "し"}},
+		{"きょう", []string{"きょ", "う"}},
+		{"しゃしん", []string{"しゃ", "し", "ん"}},
+		{"がっこう", []string{"が", "っ", "こ", "う"}},
+		{"ラーメン", []string{"ラ", "ー", "メ", "ン"}},
+		{"ファイル", []string{"ファ", "イ", "ル"}},
+		{"ちょっと", []string{"ちょ", "っ", "と"}},
+		{"ょ", []string{"ょ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kana, func(t *testing.T) {
+			if got := Morae(tt.kana); !slices.Equal(got, tt.want) {
+				t.Errorf("Morae(%q) = %q, want %q", tt.kana, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPitchAccentPattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		accent PitchAccent
+		want   PitchPattern
+		high   []bool
+	}{
+		{"heiban", PitchAccent{"はな", 0}, Heiban, []bool{false, true}},
+		{"odaka", PitchAccent{"はな", 2}, Odaka, []bool{false, true}},
+		{"atamadaka", PitchAccent{"いのち", 1}, Atamadaka, []bool{true, false, false}},
+		{"nakadaka", PitchAccent{"こころ", 2}, Nakadaka, []bool{false, true, false}},
+		{"nakadaka in four morae", PitchAccent{"ひこうき", 2}, Nakadaka, []bool{false, true, false, false}},
+		{"odaka counting morae", PitchAccent{"いもうと", 4}, Odaka, []bool{false, true, true, true}},
+		{"heiban with a youon", PitchAccent{"きょう", 0}, Heiban, []bool{false, true}},
+		{"atamadaka with a youon", PitchAccent{"きょう", 1}, Atamadaka, []bool{true, false}},
+		{"one mora heiban", PitchAccent{"は", 0}, Heiban, []bool{false}},
+		{"one mora accented", PitchAccent{"め", 1}, Atamadaka, []bool{true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.accent.Pattern(); got != tt.want {
+				t.Errorf("%+v Pattern() = %s, want %s", tt.accent, got, tt.want)
+			}
+
+			high := make([]bool, len(Morae(tt.accent.Reading)))
+			for i := range high {
+				high[i] = tt.accent.High(i)
+			}
+
+			if !slices.Equal(high, tt.high) {
+				t.Errorf("%+v High() = %v, want %v", tt.accent, high, tt.high)
+			}
+		})
+	}
+}
+
+func TestOpenPitchAccents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accents.txt")
+	data := "箸\tはし\t1\n" +
+		"橋\tはし\t2\n" +
+		"アイス\t\t1\n" +
+		"明日\tあした\t(名)0,2\n" +
+		"上手\tじょうず\t(名)3,(形動)0\n" +
+		"何\tなに\tx\n" +
+		"too\tfew\n" +
+		"\t\t1\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pa, err := OpenPitchAccents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pa.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", pa.Len())
+	}
+
+	tests := []struct {
+		word, reading string
+		want          []int
+	}{
+		{"箸", "はし", []int{1}},
+		{"橋", "はし", []int{2}},
+		{"", "アイス", []int{1}},
+		{"明日", "あした", []int{0, 2}},
+		{"上手", "じょうず", []int{3, 0}},
+		{"何", "なに", nil},
+		{"箸", "ばし", nil},
+	}
+
+	for _, tt := range tests {
+		var got []int
+		for _, a := range pa.Lookup(tt.word, tt.reading) {
+			if a.Reading != tt.reading {
+				t.Errorf("Lookup(%q, %q) reading = %q", tt.word, tt.reading, a.Reading)
+			}
+			got = append(got, a.Downstep)
+		}
+
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Lookup(%q, %q) = %v, want %v", tt.word, tt.reading, got, tt.want)
+		}
+	}
+}
+
+func TestOpenPitchAccentsMissing(t *testing.T) {
+	pa, err := OpenPitchAccents(filepath.Join(t.TempDir(), "missing.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pa.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", pa.Len())
+	}
+}
@@ -15,6 +15,7 @@ type Explainer interface {
 }
 
 type Chatter interface {
+	Chat(ctx context.Context, content string) (string, error)
 }
 
 type ChatBot interface {
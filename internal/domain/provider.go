@@ -0,0 +1,48 @@
+package domain
+
+import "fmt"
+
+const (
+	ProviderDeepL          = "DeepL"
+	ProviderLibreTranslate = "LibreTranslate"
+	ProviderLLM            = "LLM"
+)
+
+// TranslatorRegistry keeps the configured translation providers by name,
+// in the order they were registered.
+type TranslatorRegistry struct {
+	names       []string
+	translators map[string]Translator
+}
+
+func NewTranslatorRegistry() *TranslatorRegistry {
+	return &TranslatorRegistry{
+		names:       make([]string, 0),
+		translators: make(map[string]Translator),
+	}
+}
+
+func (r *TranslatorRegistry) Register(name string, t Translator) {
+	if _, ok := r.translators[name]; !ok {
+		r.names = append(r.names, name)
+	}
+
+	r.translators[name] = t
+}
+
+func (r *TranslatorRegistry) Get(name string) (Translator, error) {
+	t, ok := r.translators[name]
+	if !ok {
+		return nil, fmt.Errorf("translator %q is not configured", name)
+	}
+
+	return t, nil
+}
+
+func (r *TranslatorRegistry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+func (r *TranslatorRegistry) Len() int {
+	return len(r.names)
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/text/encoding/japanese"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Radical is a component of RADKFILE. Some are stood in for by a kanji containing them,
+// the way the file writes them.
+type Radical struct {
+	Literal rune
+	Strokes int
+}
+
+// Radicals indexes kanji by their components, from local RADKFILE and KRADFILE files.
+type Radicals struct {
+	list       []Radical              // in stroke order, as listed by RADKFILE
+	kanji      map[rune][]rune        // radical to the kanji containing it
+	radicals   map[rune]map[rune]bool // kanji to the radicals RADKFILE lists it under
+	components map[rune][]rune        // kanji to its radicals, from KRADFILE
+}
+
+// OpenRadicals loads RADKFILE and KRADFILE, in EUC-JP as distributed or converted to UTF-8.
+// Missing files leave the index empty.
+func OpenRadicals(radkPath, kradPath string) (*Radicals, error) {
+	r := &Radicals{
+		kanji:      make(map[rune][]rune),
+		radicals:   make(map[rune]map[rune]bool),
+		components: make(map[rune][]rune),
+	}
+
+	if err := readRadicalFile(radkPath, r.parseRadk); err != nil {
+		return nil, fmt.Errorf("load radkfile: %w", err)
+	}
+
+	if err := readRadicalFile(kradPath, r.parseKrad); err != nil {
+		return nil, fmt.Errorf("load kradfile: %w", err)
+	}
+
+	return r, nil
+}
+
+// readRadicalFile hands the lines of the file to parse, skipping comments.
+func readRadicalFile(path string, parse func(line string) error) error {
+	f, err := openDataset(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	if !utf8.Valid(b) {
+		if b, err = japanese.EUCJP.NewDecoder().Bytes(b); err != nil {
+			return err
+		}
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err = parse(line); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
+
+// parseRadk reads "$ 一 1" radical lines, and the lines of kanji following them.
+func (r *Radicals) parseRadk(line string) error {
+	if !strings.HasPrefix(line, "$") {
+		if len(r.list) == 0 {
+			return fmt.Errorf("kanji before the first radical: %q", line)
+		}
+
+		rad := r.list[len(r.list)-1].Literal
+		for _, k := range line {
+			r.kanji[rad] = append(r.kanji[rad], k)
+			if r.radicals[k] == nil {
+				r.radicals[k] = make(map[rune]bool)
+			}
+			r.radicals[k][rad] = true
+		}
+		return nil
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 || utf8.RuneCountInString(fields[1]) != 1 {
+		return fmt.Errorf("invalid radical line: %q", line)
+	}
+
+	strokes, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return fmt.Errorf("invalid radical line: %q", line)
+	}
+
+	rad, _ := utf8.DecodeRuneInString(fields[1])
+	r.list = append(r.list, Radical{Literal: rad, Strokes: strokes})
+	return nil
+}
+
+// parseKrad reads "亜 : ｜ 一 口" lines.
+func (r *Radicals) parseKrad(line string) error {
+	k, rads, ok := strings.Cut(line, ":")
+	k = strings.TrimSpace(k)
+	if !ok || utf8.RuneCountInString(k) != 1 {
+		return fmt.Errorf("invalid kanji line: %q", line)
+	}
+
+	kanji, _ := utf8.DecodeRuneInString(k)
+	for _, f := range strings.Fields(rads) {
+		rad, _ := utf8.DecodeRuneInString(f)
+		r.components[kanji] = append(r.components[kanji], rad)
+	}
+
+	return nil
+}
+
+// List is every radical, in stroke order.
+func (r *Radicals) List() []Radical {
+	return r.list
+}
+
+// Candidates are the kanji containing every one of the radicals, none without radicals.
+func (r *Radicals) Candidates(radicals []rune) []rune {
+	if len(radicals) == 0 {
+		return nil
+	}
+
+	return slices.DeleteFunc(slices.Clone(r.kanji[radicals[0]]), func(k rune) bool {
+		for _, rad := range radicals[1:] {
+			if !r.radicals[k][rad] {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Present are the radicals found in at least one of the kanji, the ones that can still
+// narrow a selection down to them.
+func (r *Radicals) Present(kanji []rune) map[rune]bool {
+	present := make(map[rune]bool)
+	for _, k := range kanji {
+		for rad := range r.radicals[k] {
+			present[rad] = true
+		}
+	}
+
+	return present
+}
+
+// Components are the radicals of a kanji, from KRADFILE.
+func (r *Radicals) Components(kanji rune) []rune {
+	return r.components[kanji]
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JLPTLevels are the JLPT tags of the dictionary, easiest first.
+var JLPTLevels = []string{"jlpt-n5", "jlpt-n4", "jlpt-n3", "jlpt-n2", "jlpt-n1"}
+
+// ResultFilter narrows search results down, zero values don't filter.
+type ResultFilter struct {
+	CommonOnly   bool
+	JLPT         string // one of JLPTLevels
+	PartOfSpeech string
+	HasKanji     bool
+}
+
+func (f ResultFilter) Match(info Information) bool {
+	if f.CommonOnly && !info.IsCommon {
+		return false
+	}
+
+	if f.JLPT != "" && !slices.Contains(info.JLPT, f.JLPT) {
+		return false
+	}
+
+	if f.PartOfSpeech != "" && !slices.Contains(PartsOfSpeech([]Information{info}), f.PartOfSpeech) {
+		return false
+	}
+
+	if f.HasKanji && !hasKanji(info) {
+		return false
+	}
+
+	return true
+}
+
+// Active reports whether the filter drops anything.
+func (f ResultFilter) Active() bool {
+	return f != ResultFilter{}
+}
+
+func hasKanji(info Information) bool {
+	return len(EntryKanji(&info)) > 0
+}
+
+// EntryKanji lists the distinct kanji of the entry's written forms, in order of appearance.
+func EntryKanji(info *Information) []rune {
+	var kanji []rune
+	for _, j := range info.Japanese {
+		for _, r := range j.Word {
+			if unicode.Is(unicode.Han, r) && !slices.Contains(kanji, r) {
+				kanji = append(kanji, r)
+			}
+		}
+	}
+
+	return kanji
+}
+
+// ContainingKanji keeps the results with a written form using the kanji.
+func ContainingKanji(infos []Information, kanji rune) []Information {
+	var res []Information
+	for _, info := range infos {
+		if slices.Contains(EntryKanji(&info), kanji) {
+			res = append(res, info)
+		}
+	}
+
+	return res
+}
+
+// PartsOfSpeech lists the parts of speech of the results' senses, in order of appearance.
+func PartsOfSpeech(infos []Information) []string {
+	var pos []string
+	for _, info := range infos {
+		for _, s := range info.Senses {
+			for _, p := range s.PartsOfSpeech {
+				if !slices.Contains(pos, p) {
+					pos = append(pos, p)
+				}
+			}
+		}
+	}
+
+	return pos
+}
+
+// ResultOrder is how search results are sorted.
+type ResultOrder int
+
+const (
+	OrderRelevance ResultOrder = iota
+	OrderCommon
+	OrderJLPT
+	OrderReadingLength
+)
+
+func (o ResultOrder) String() string {
+	if o < OrderRelevance || o > OrderReadingLength {
+		return "Unknown"
+	}
+
+	return [...]string{"relevance", "commonness", "JLPT level", "reading length"}[o]
+}
+
+// Next cycles through the orders.
+func (o ResultOrder) Next() ResultOrder {
+	return (o + 1) % (OrderReadingLength + 1)
+}
+
+// FilterResults keeps the results matching f, sorted by order. Relevance is the order of the
+// dictionary, the other orders keep it among equals.
+func FilterResults(infos []Information, f ResultFilter, order ResultOrder) []Information {
+	res := make([]Information, 0, len(infos))
+	for _, info := range infos {
+		if f.Match(info) {
+			res = append(res, info)
+		}
+	}
+
+	switch order {
+	case OrderCommon:
+		slices.SortStableFunc(res, func(a, b Information) int {
+			return boolRank(b.IsCommon) - boolRank(a.IsCommon)
+		})
+
+	case OrderJLPT:
+		// easiest level first, untagged words last
+		slices.SortStableFunc(res, func(a, b Information) int {
+			return jlptRank(a) - jlptRank(b)
+		})
+
+	case OrderReadingLength:
+		slices.SortStableFunc(res, func(a, b Information) int {
+			return readingLength(a) - readingLength(b)
+		})
+	}
+
+	return res
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func jlptRank(info Information) int {
+	rank := len(JLPTLevels)
+	for _, l := range info.JLPT {
+		if i := slices.Index(JLPTLevels, l); i >= 0 {
+			rank = min(rank, i)
+		}
+	}
+
+	return rank
+}
+
+func readingLength(info Information) int {
+	if len(info.Japanese) == 0 {
+		return utf8.RuneCountInString(info.Slug)
+	}
+
+	return utf8.RuneCountInString(info.Japanese[0].Reading)
+}
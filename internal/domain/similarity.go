@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DriftThreshold is the similarity below which a round-tripped sentence is considered drifted.
+const DriftThreshold = 0.6
+
+func levenshtein(a, b []rune) int {
+	if len(a) == 0 {
+		return len(b)
+	}
+
+	if len(b) == 0 {
+		return len(a)
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func normalize(s string) []rune {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case unicode.IsPunct(r):
+			continue
+		default:
+			if space && b.Len() > 0 {
+				b.WriteRune(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		}
+	}
+
+	return []rune(b.String())
+}
+
+// Similarity scores two strings from 0 to 1 by their normalized edit distance,
+// ignoring case, punctuation and repeated whitespace.
+func Similarity(a, b string) float64 {
+	return similarity(normalize(a), normalize(b))
+}
+
+func similarity(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// similarityBound is a cheap upper bound of similarity: an edit keeps at most the characters
+// both strings have in common, so the distance is at least the longest length minus their count.
+func similarityBound(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+
+	counts := make(map[rune]int, len(a))
+	for _, r := range a {
+		counts[r]++
+	}
+
+	var common int
+	for _, r := range b {
+		if counts[r] > 0 {
+			counts[r]--
+			common++
+		}
+	}
+
+	return float64(common) / float64(longest)
+}
+
+// SplitSentences splits on western and Japanese sentence terminators, keeping them.
+func SplitSentences(s string) []string {
+	var sentences []string
+	var b strings.Builder
+
+	flush := func() {
+		if t := strings.TrimSpace(b.String()); t != "" {
+			sentences = append(sentences, t)
+		}
+		b.Reset()
+	}
+
+	for _, r := range s {
+		b.WriteRune(r)
+		switch r {
+		case '.', '!', '?', '。', '！', '？', '\n':
+			flush()
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+type SentenceDrift struct {
+	Original  string
+	RoundTrip string
+	Score     float64
+	Drifted   bool
+}
+
+type RoundTrip struct {
+	Via       TargetLang
+	Score     float64
+	Sentences []SentenceDrift
+}
+
+// CompareRoundTrip pairs the sentences of the original and the back-translated text by position.
+func CompareRoundTrip(via TargetLang, original, back string) RoundTrip {
+	ots, bts := SplitSentences(original), SplitSentences(back)
+
+	rt := RoundTrip{
+		Via:       via,
+		Score:     Similarity(original, back),
+		Sentences: make([]SentenceDrift, max(len(ots), len(bts))),
+	}
+
+	for i := range rt.Sentences {
+		var o, b string
+		if i < len(ots) {
+			o = ots[i]
+		}
+		if i < len(bts) {
+			b = bts[i]
+		}
+
+		score := Similarity(o, b)
+		rt.Sentences[i] = SentenceDrift{Original: o, RoundTrip: b, Score: score, Drifted: score < DriftThreshold}
+	}
+
+	return rt
+}
+
+// BackTranslate translates every group back into the source language and scores the round trip.
+func BackTranslate(ctx context.Context, t Translator, source TargetLang, original string, groups []TranslationGroup) ([]RoundTrip, error) {
+	trips := make([]RoundTrip, 0, len(groups))
+	for _, g := range groups {
+		texts := make([]string, len(g.Translations))
+		for i, tr := range g.Translations {
+			texts[i] = tr.Text
+		}
+
+		back, err := t.Translate(ctx, source, texts...)
+		if err != nil {
+			return nil, fmt.Errorf("back-translate from %s: %w", g.Lang, err)
+		}
+
+		backTexts := make([]string, len(back))
+		for i, tr := range back {
+			backTexts[i] = tr.Text
+		}
+
+		trips = append(trips, CompareRoundTrip(g.Lang, original, strings.Join(backTexts, "\n")))
+	}
+
+	return trips, nil
+}
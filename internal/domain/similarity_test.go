@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"食べる", "食べた", 1},
+		{"たべる", "食べる", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+				t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := levenshtein([]rune(tt.b), []rune(tt.a)); got != tt.want {
+				t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"both empty", "", "", 1},
+		{"one empty", "", "abc", 0},
+		{"identical", "I eat sushi.", "I eat sushi.", 1},
+		{"case and punctuation", "I eat sushi.", "i eat sushi", 1},
+		{"repeated whitespace", "  I   eat\tsushi ", "I eat sushi", 1},
+		{"japanese punctuation", "寿司を食べる。", "寿司を食べる", 1},
+		{"one edit in four", "abcd", "abce", 0.75},
+		{"nothing in common", "abc", "xyz", 0},
+		{"kitten", "kitten", "sitting", 1 - 3.0/7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarityBound(t *testing.T) {
+	pairs := [][2]string{
+		{"", ""},
+		{"abc", ""},
+		{"kitten", "sitting"},
+		{"abcd", "dcba"},
+		{"寿司を食べる", "寿司を食べた"},
+		{"I eat sushi", "sushi I eat"},
+	}
+
+	for _, p := range pairs {
+		a, b := normalize(p[0]), normalize(p[1])
+		if bound, got := similarityBound(a, b), similarity(a, b); bound < got {
+			t.Errorf("similarityBound(%q, %q) = %v, below the similarity %v", p[0], p[1], bound, got)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -44,6 +46,30 @@ func (t TargetLang) String() string {
 	return [...]string{"Japanese", "English", "Indonesian"}[t]
 }
 
+// SplitParagraphs splits text on blank lines so every paragraph can be translated as its own segment.
+func SplitParagraphs(text string) []string {
+	paragraphs := make([]string, 0)
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+
+	return paragraphs
+}
+
+// ParseTargetLang maps a language code, as reported in Translation.DetectedSourceLanguage, to a TargetLang.
+func ParseTargetLang(code string) (TargetLang, error) {
+	code = strings.ToUpper(code)
+	for t := TargetJapanese; t <= TargetIndonesia; t++ {
+		if strings.HasPrefix(code, t.Code()) {
+			return t, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported language %q", code)
+}
+
 type translator struct {
 	client *http.Client
 
@@ -120,3 +146,40 @@ func (t *translator) Translate(ctx context.Context, lang TargetLang, texts ...st
 
 	return deep.Translations, nil
 }
+
+// TranslationGroup holds the translations of the same texts into one target language.
+type TranslationGroup struct {
+	Lang         TargetLang
+	Translations []Translation
+}
+
+// TranslateMany translates the texts into every language concurrently.
+// The groups follow the order of langs; the first failure is returned.
+func TranslateMany(ctx context.Context, t Translator, langs []TargetLang, texts ...string) ([]TranslationGroup, error) {
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("no target language selected")
+	}
+
+	groups := make([]TranslationGroup, len(langs))
+	errs := make([]error, len(langs))
+
+	var wg sync.WaitGroup
+	for i, lang := range langs {
+		wg.Add(1)
+		go func(i int, lang TargetLang) {
+			defer wg.Done()
+
+			groups[i].Lang = lang
+			groups[i].Translations, errs[i] = t.Translate(ctx, lang, texts...)
+		}(i, lang)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("translate to %s: %w", langs[i], err)
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+)
+
+type verdictMsg struct {
+	res *domain.Verdict
+	err error
+}
+
+type CompareModel struct {
+	width int
+
+	input string
+	lang  domain.TargetLang
+	res   []domain.Comparison
+
+	asking  bool
+	verdict *domain.Verdict
+	err     error
+
+	chat domain.Chatter
+}
+
+func NewCompareModel(chat domain.Chatter) *CompareModel {
+	return &CompareModel{
+		width: 100,
+		res:   make([]domain.Comparison, 0),
+		chat:  chat,
+	}
+}
+
+func (cm *CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+func (cm *CompareModel) askCmd(ctx context.Context) tea.Cmd {
+	input, lang, res := cm.input, cm.lang, cm.res
+	return func() tea.Msg {
+		v, err := domain.AskMostNatural(ctx, cm.chat, lang, input, res)
+		return verdictMsg{res: v, err: err}
+	}
+}
+
+func (cm *CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cm.width = msg.Width
+		return cm, nil
+
+	case verdictMsg:
+		cm.asking = false
+		cm.verdict, cm.err = msg.res, msg.err
+		return cm, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Explain):
+			if cm.asking || cm.verdict != nil {
+				return cm, nil
+			}
+
+			cm.asking = true
+			cm.err = nil
+			return cm, cm.askCmd(context.Background())
+
+		case key.Matches(msg, keys.Back):
+			return cm, func() tea.Msg {
+				return navigateBack{}
+			}
+
+		case key.Matches(msg, keys.Quit):
+			return cm, tea.Quit
+		}
+	}
+
+	return cm, nil
+}
+
+func (cm *CompareModel) View() string {
+	fnt := view.FootNoteStyle.Padding(1, 0, 2, 4).Render(
+		footer(StateCompare),
+	)
+
+	if len(cm.res) == 0 {
+		return view.BaseViewStyle.Render("Nothing to compare") + fnt
+	}
+
+	body := view.WordStyle.Render("Compared translations to "+cm.lang.String()+":") + "\n\n" +
+		view.RenderComparison(cm.res, cm.width-view.PaddingLeftTwo*2)
+
+	switch {
+	case cm.asking:
+		body += "\n\n" + view.MutedStyle.Render("Asking the explainer...")
+	case cm.err != nil:
+		body += "\n\n" + view.MutedStyle.Render("Explainer error: "+cm.err.Error())
+	case cm.verdict != nil:
+		body += "\n\n" + view.RenderVerdict(cm.verdict)
+	}
+
+	return view.BaseViewStyle.Render(body) + fnt
+}
+
+func (cm *CompareModel) SetItem(input string, lang domain.TargetLang, res []domain.Comparison) tea.Cmd {
+	cm.input, cm.lang, cm.res = input, lang, res
+	cm.asking, cm.verdict, cm.err = false, nil, nil
+	return nil
+}
+
+func (cm *CompareModel) Snapshot() tea.Model {
+	c := *cm
+	return &c
+}
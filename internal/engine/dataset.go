@@ -0,0 +1,31 @@
+package engine
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+)
+
+// datasetOpened tells the engine a local dataset finished opening in the background, the
+// screens are laid out again to show it.
+type datasetOpened struct {
+	err error
+}
+
+// openCmd parses a local dataset off the UI goroutine.
+func openCmd[T any](l *domain.Lazy[T]) tea.Cmd {
+	return func() tea.Msg {
+		_, err := l.Get()
+		return datasetOpened{err}
+	}
+}
+
+// dataset is the opened dataset, or an empty one with the command opening it while it isn't
+// ready yet, so the screens show a loading state instead of waiting on the file.
+func dataset[T any](l *domain.Lazy[T]) (*T, tea.Cmd) {
+	if !l.Ready() {
+		return new(T), openCmd(l)
+	}
+
+	v, _ := l.Get()
+	return v, nil
+}
@@ -1,26 +1,51 @@
 package engine
 
 import (
-	"github.com/charmbracelet/bubbles/viewport"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
-	"github.com/muesli/termenv"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/view"
+	"strings"
 )
 
+// entryTab is a tab of the dictionary detail view, see entryTabs.
+type entryTab int
+
+const (
+	tabSenses entryTab = iota
+	tabForms
+	tabKanji
+	tabExamples
+	tabRelated
+)
+
+var entryTabs = []string{"Senses", "Forms & readings", "Kanji", "Examples", "Related"}
+
+// maxExamples is how many example sentences the Examples tab shows.
+const maxExamples = 10
+
 type DictionaryDetailModel struct {
-	viewport viewport.Model
+	viewport pager
+	kanji    *domain.Lazy[domain.Kanjidic]
+	examples *domain.Lazy[domain.Examples]
+	pitch    *domain.Lazy[domain.PitchAccents]
 
-	detail *domain.Information
+	detail  *domain.Information
+	sense   int
+	example int // selected on the Examples tab
+	tab     entryTab
 }
 
-func NewDictionaryDetailModel() *DictionaryDetailModel {
-	vp := viewport.New(78, 12)
-	vp.Style = view.BorderStyle.PaddingRight(2)
-
+func NewDictionaryDetailModel(kanji *domain.Lazy[domain.Kanjidic], examples *domain.Lazy[domain.Examples], pitch *domain.Lazy[domain.PitchAccents]) *DictionaryDetailModel {
 	return &DictionaryDetailModel{
-		viewport: vp,
+		viewport: newPager(78, 12),
+		kanji:    kanji,
+		examples: examples,
+		pitch:    pitch,
 
 		detail: nil,
 	}
@@ -34,23 +59,95 @@ func (ddm *DictionaryDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		ddm.viewport.Width = msg.Width
+		ddm.viewport.Height = max(msg.Height-view.FooterHeight-1-2, view.MinViewportHeight) // pager status line, header and tabs
+		if ddm.detail == nil {
+			return ddm, nil
+		}
+
+		return ddm, ddm.render()
+
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft && msg.Y == 1 {
+			if i := view.TabAt(entryTabs, msg.X-view.PaddingLeftOne); i >= 0 {
+				return ddm, ddm.switchTab(entryTab(i))
+			}
+		}
+
+		return ddm, ddm.viewport.Update(msg)
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlS:
+		if ddm.viewport.Searching() {
+			return ddm, ddm.viewport.Update(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.NewSearch):
 			return ddm, func() tea.Msg {
 				return switchToSearch{}
 			}
 
-		case tea.KeyCtrlQ:
+		case key.Matches(msg, keys.Back):
+			return ddm, func() tea.Msg {
+				return navigateBack{}
+			}
+
+		case key.Matches(msg, keys.NextTab):
+			return ddm, ddm.switchTab((ddm.tab + 1) % entryTab(len(entryTabs)))
+
+		case key.Matches(msg, keys.PrevTab):
+			return ddm, ddm.switchTab((ddm.tab - 1 + entryTab(len(entryTabs))) % entryTab(len(entryTabs)))
+
+		case key.Matches(msg, keys.Next):
+			if ddm.tab == tabExamples {
+				return ddm, ddm.moveExample(1)
+			}
+
+			if ddm.detail == nil || len(ddm.detail.Senses) == 0 {
+				return ddm, nil
+			}
+
+			ddm.sense = (ddm.sense + 1) % len(ddm.detail.Senses)
+			return ddm, ddm.render()
+
+		case key.Matches(msg, keys.Prev):
+			if ddm.tab == tabExamples {
+				return ddm, ddm.moveExample(-1)
+			}
+
+			if ddm.detail == nil || len(ddm.detail.Senses) == 0 {
+				return ddm, nil
+			}
+
+			ddm.sense = (ddm.sense - 1 + len(ddm.detail.Senses)) % len(ddm.detail.Senses)
+			return ddm, ddm.render()
+
+		case key.Matches(msg, keys.Translate):
+			text := ddm.senseText()
+			if text == "" {
+				return ddm, nil
+			}
+
+			return ddm, func() tea.Msg {
+				return handoff{to: StateTranslate, text: text}
+			}
+
+		case key.Matches(msg, keys.Explain):
+			text := ddm.explainText()
+			if text == "" {
+				return ddm, nil
+			}
+
 			return ddm, func() tea.Msg {
-				return switchToDictionaryOld{}
+				return handoff{to: StateExplainer, text: text}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return ddm, tea.Quit
 
 		default:
-			ddm.viewport, cmd = ddm.viewport.Update(msg)
+			cmd = ddm.viewport.Update(msg)
 			return ddm, cmd
 		}
 	}
@@ -59,19 +156,91 @@ func (ddm *DictionaryDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (ddm *DictionaryDetailModel) View() string {
-	return ddm.viewport.View() + view.FootNoteStyle.Padding(1, 0, 2, 4).Render(
-		" ↑/k up • ↓/j down • ctrl+s: back to search • ctrl+q: back to dictionary\n",
+	var sense string
+	if ddm.detail != nil && len(ddm.detail.Senses) > 0 {
+		sense = fmt.Sprintf("sense %d/%d • ", ddm.sense+1, len(ddm.detail.Senses))
+	}
+
+	var header string
+	if ddm.detail != nil {
+		pitch, _ := dataset(ddm.pitch) // render opens it
+		// one line each, the viewport height and the tab clicks count on it
+		width := ddm.viewport.Width - view.PaddingLeftOne
+		pad := lipgloss.NewStyle().PaddingLeft(view.PaddingLeftOne)
+		header = pad.Render(ansi.Truncate(view.RenderEntryHeader(ddm.detail, pitch), width, "…")) + "\n" +
+			pad.Render(ansi.Truncate(view.RenderTabs(entryTabs, int(ddm.tab)), width, "…")) + "\n"
+	}
+
+	return header + ddm.viewport.View() + view.FootNoteStyle.Padding(1, 0, 2, 4).Render(
+		sense+footer(StateDetail),
 	)
 }
 
+// senseText joins the English definitions of the selected sense, ready to be translated.
+func (ddm *DictionaryDetailModel) senseText() string {
+	if ddm.detail == nil || ddm.sense >= len(ddm.detail.Senses) {
+		return ""
+	}
+
+	return strings.Join(ddm.detail.Senses[ddm.sense].EnglishDefinitions, "; ")
+}
+
+// headword is the first written form of the entry, falling back to its reading.
+func (ddm *DictionaryDetailModel) headword() string {
+	if ddm.detail == nil || len(ddm.detail.Japanese) == 0 {
+		return ""
+	}
+
+	if w := ddm.detail.Japanese[0].Word; w != "" {
+		return w
+	}
+
+	return ddm.detail.Japanese[0].Reading
+}
+
+// shownExamples are the example sentences of the Examples tab.
+func (ddm *DictionaryDetailModel) shownExamples() []domain.Example {
+	examples, _ := dataset(ddm.examples) // render opens it
+	return examples.Find(ddm.headword(), maxExamples)
+}
+
+// moveExample selects another example sentence, wrapping around.
+func (ddm *DictionaryDetailModel) moveExample(delta int) tea.Cmd {
+	n := len(ddm.shownExamples())
+	if n == 0 {
+		return nil
+	}
+
+	ddm.example = (ddm.example + delta + n) % n
+	return ddm.render()
+}
+
+// explainText is the example sentence selected on the Examples tab, or else the headword.
+func (ddm *DictionaryDetailModel) explainText() string {
+	if examples := ddm.shownExamples(); ddm.tab == tabExamples && ddm.example < len(examples) {
+		return examples[ddm.example].Japanese
+	}
+
+	return ddm.headword()
+}
+
 func (ddm *DictionaryDetailModel) SetItem(detail *domain.Information) tea.Cmd {
 	ddm.detail = detail
+	ddm.sense = 0
+	ddm.example = 0
+	ddm.tab = tabSenses
+
+	cmd := ddm.render()
+	ddm.viewport.GotoTop()
+	return cmd
+}
 
-	glamourRenderWidth := 78 - ddm.viewport.Style.GetHorizontalFrameSize() - 2
+// render runs the entry through glamour, wrapping to the current viewport width.
+func (ddm *DictionaryDetailModel) render() tea.Cmd {
 	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(glamourRenderWidth),
-		glamour.WithColorProfile(termenv.ANSI256),
+		glamour.WithStyles(view.GlamourStyle()),
+		glamour.WithWordWrap(ddm.contentWidth()),
+		glamour.WithColorProfile(view.ColorProfile()),
 	)
 
 	if err != nil {
@@ -81,10 +250,11 @@ func (ddm *DictionaryDetailModel) SetItem(detail *domain.Information) tea.Cmd {
 	}
 
 	var content string
+	var open tea.Cmd
 	if ddm.detail == nil {
 		content = "Details not found"
 	} else {
-		content = view.RenderEntry(ddm.detail)
+		content, open = ddm.renderTab()
 	}
 
 	str, err := renderer.Render(content)
@@ -95,5 +265,96 @@ func (ddm *DictionaryDetailModel) SetItem(detail *domain.Information) tea.Cmd {
 	}
 
 	ddm.viewport.SetContent(str)
-	return nil
+	return open
+}
+
+// contentWidth is the room glamour has to wrap to in the viewport.
+func (ddm *DictionaryDetailModel) contentWidth() int {
+	return ddm.viewport.Width - ddm.viewport.Style.GetHorizontalFrameSize() - 2
+}
+
+// switchTab shows another tab from its top.
+func (ddm *DictionaryDetailModel) switchTab(tab entryTab) tea.Cmd {
+	ddm.tab = tab
+	cmd := ddm.render()
+	ddm.viewport.GotoTop()
+	return cmd
+}
+
+// renderTab is the markdown of the current tab, with the commands opening the datasets it
+// shows while they aren't yet.
+func (ddm *DictionaryDetailModel) renderTab() (string, tea.Cmd) {
+	pitch, open := dataset(ddm.pitch) // the header shows it on every tab
+
+	switch ddm.tab {
+	case tabForms:
+		return view.RenderForms(ddm.detail, pitch), open
+	case tabKanji:
+		kanji, openKanji := dataset(ddm.kanji)
+		if openKanji != nil {
+			return "_Loading KANJIDIC2…_", tea.Batch(open, openKanji)
+		}
+
+		return view.RenderKanji(ddm.detail, kanji), open
+	case tabExamples:
+		examples, openExamples := dataset(ddm.examples)
+		if openExamples != nil {
+			return "_Loading the example sentences…_", tea.Batch(open, openExamples)
+		}
+
+		return view.RenderExamples(ddm.headword(), ddm.shownExamples(), ddm.example, examples.Len() > 0, ddm.contentWidth()), open
+	case tabRelated:
+		return view.RenderRelated(ddm.detail), open
+	default:
+		return view.RenderSenses(ddm.detail, ddm.sense), open
+	}
+}
+
+func (ddm *DictionaryDetailModel) Commands() []command {
+	var commands []command
+	if word := ddm.headword(); word != "" {
+		title := "Explain " + word
+		if text := ddm.explainText(); text != word {
+			word, title = text, "Explain the selected example"
+		}
+
+		commands = append(commands, command{
+			title: title,
+			run:   dispatch(handoff{to: StateExplainer, text: word}),
+		})
+	}
+
+	if text := ddm.senseText(); text != "" {
+		commands = append(commands, command{
+			title: "Translate the selected sense",
+			run:   dispatch(handoff{to: StateTranslate, text: text}),
+		})
+	}
+
+	return commands
+}
+
+func (ddm *DictionaryDetailModel) Capturing() bool {
+	return ddm.viewport.Searching()
+}
+
+func (ddm *DictionaryDetailModel) applyTheme() {
+	ddm.viewport.applyTheme()
+}
+
+func (ddm *DictionaryDetailModel) Snapshot() tea.Model {
+	c := *ddm
+	return &c
+}
+
+func (ddm *DictionaryDetailModel) Breadcrumb() string {
+	if w := ddm.headword(); w != "" {
+		return w
+	}
+
+	if ddm.detail != nil {
+		return ddm.detail.Slug
+	}
+
+	return ""
 }
@@ -5,20 +5,21 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/view"
 	"io"
-	"strings"
+	"slices"
 )
 
 type entry struct {
 }
 
 func (d entry) Height() int {
-	return 1
+	return 2
 }
 func (d entry) Spacing() int {
-	return 0
+	return 1
 }
 func (d entry) Update(_ tea.Msg, _ *list.Model) tea.Cmd {
 	return nil
@@ -30,43 +31,42 @@ func (d entry) Render(w io.Writer, m list.Model, index int, listItem list.Item)
 		return
 	}
 
-	fn := view.NormalStyle.Render
-	if index == m.Index() {
-		fn = func(s ...string) string {
-			return view.HighlightStyle.Render("> " + strings.Join(s, " "))
-		}
-	}
-
-	_, _ = fmt.Fprint(w, fn(fmt.Sprintf("%d. %s", index+1, i.Slug)))
+	_, _ = fmt.Fprint(w, view.RenderResult(index, (*domain.Information)(&i), index == m.Index(), m.Width()))
 }
 
 type DictionaryModel struct {
-	list list.Model
+	list  list.Model
+	query string
+
+	// all are the results of the search, the list shows those matching the facets
+	all    []domain.Information
+	filter domain.ResultFilter
+	order  domain.ResultOrder
 }
 
 func NewDictionaryModel() *DictionaryModel {
 	l := list.New(make([]list.Item, 0), entry{}, 50, 15)
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
-	l.Styles.Title = view.HighlightStyle
+	l.KeyMap.Filter = keys.Find
 	l.Styles.PaginationStyle = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
-	l.Styles.HelpStyle = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
-	l.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(
-				key.WithKeys("enter"),
-				key.WithHelp("enter", "select entry"),
-			),
-			key.NewBinding(
-				key.WithKeys("backspace"),
-				key.WithHelp("backspace", "back to search"),
-			),
-		}
-	}
-
-	return &DictionaryModel{
+	// the engine owns quitting and the help, the list only moves the cursor
+	l.SetShowHelp(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ShowFullHelp.SetEnabled(false)
+	l.KeyMap.CloseFullHelp.SetEnabled(false)
+	// esc goes back, clear_filters drops the filter
+	l.KeyMap.ClearFilter.SetEnabled(false)
+
+	dm := &DictionaryModel{
 		list: l,
 	}
+
+	dm.applyTheme()
+	return dm
+}
+
+func (dm *DictionaryModel) applyTheme() {
+	dm.list.Styles.Title = view.HighlightStyle
 }
 
 func (dm *DictionaryModel) Init() tea.Cmd {
@@ -78,31 +78,78 @@ func (dm *DictionaryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		dm.list.SetWidth(msg.Width)
+		dm.list.SetSize(msg.Width, max(msg.Height-1-view.FooterHeight, view.ListHeight))
 		return dm, nil
 
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
-			i, ok := dm.list.SelectedItem().(item)
-			switch ok {
-			case true:
-				return dm, func() tea.Msg {
-					return switchToDetail{res: (*domain.Information)(&i)}
-				}
-			default:
-				return dm, func() tea.Msg {
-					return switchToError{err: fmt.Errorf("invalid item type")}
-				}
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return dm, nil
+		}
+
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			dm.list.CursorUp()
+
+		case tea.MouseButtonWheelDown:
+			dm.list.CursorDown()
+
+		case tea.MouseButtonLeft:
+			// a click selects an entry, a click on the selected one opens it
+			i, ok := dm.itemAt(msg.Y)
+			if !ok {
+				return dm, nil
+			}
+
+			if i == dm.list.Index() {
+				return dm, dm.open()
 			}
 
-		case tea.KeyCtrlQ:
-			dm.list.ResetSelected()
+			dm.list.Select(i)
+		}
+
+		return dm, nil
+
+	case tea.KeyMsg:
+		if dm.list.SettingFilter() {
+			dm.list, cmd = dm.list.Update(msg)
+			return dm, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Select):
+			return dm, dm.open()
+
+		case key.Matches(msg, keys.ToggleCommon):
+			dm.filter.CommonOnly = !dm.filter.CommonOnly
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.CycleJLPT):
+			dm.filter.JLPT = cycle(domain.JLPTLevels, dm.filter.JLPT)
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.CyclePOS):
+			dm.filter.PartOfSpeech = cycle(domain.PartsOfSpeech(dm.all), dm.filter.PartOfSpeech)
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.ToggleKanji):
+			dm.filter.HasKanji = !dm.filter.HasKanji
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.CycleSort):
+			dm.order = dm.order.Next()
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.ClearFilters):
+			dm.filter = domain.ResultFilter{}
+			dm.list.ResetFilter()
+			return dm, dm.refresh()
+
+		case key.Matches(msg, keys.Back):
 			return dm, func() tea.Msg {
-				return switchToSearch{}
+				return navigateBack{}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return dm, tea.Quit
 		}
 	}
@@ -111,21 +158,90 @@ func (dm *DictionaryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return dm, cmd
 }
 
-func (dm *DictionaryModel) SetItems(infos []domain.Information) tea.Cmd {
+// open shows the selected entry.
+func (dm *DictionaryModel) open() tea.Cmd {
+	i, ok := dm.list.SelectedItem().(item)
+	switch ok {
+	case true:
+		return func() tea.Msg {
+			return switchToDetail{res: (*domain.Information)(&i)}
+		}
+	default:
+		return func() tea.Msg {
+			return switchToError{err: fmt.Errorf("invalid item type")}
+		}
+	}
+}
+
+// itemAt is the index of the entry drawn on row y of the view, below the title bar.
+func (dm *DictionaryModel) itemAt(y int) (int, bool) {
+	row := y - 1 - lipgloss.Height(dm.list.Styles.TitleBar.Render(dm.list.Title)) // the view starts with a blank line
+	if row < 0 {
+		return 0, false
+	}
+
+	start, end := dm.list.Paginator.GetSliceBounds(len(dm.list.VisibleItems()))
+	height := entry{}.Height() + entry{}.Spacing()
+	i := start + row/height
+	return i, row%height < entry{}.Height() && i < end
+}
+
+func (dm *DictionaryModel) SetItems(query string, infos []domain.Information) tea.Cmd {
+	dm.query = query
+	dm.all = infos
+	dm.filter = domain.ResultFilter{}
+	dm.order = domain.OrderRelevance
+	dm.list.ResetFilter()
+
+	return dm.refresh()
+}
+
+// refresh lists the results matching the facets, in the chosen order.
+func (dm *DictionaryModel) refresh() tea.Cmd {
+	infos := domain.FilterResults(dm.all, dm.filter, dm.order)
 	items := make([]list.Item, len(infos))
 	for i, info := range infos {
 		items[i] = item(info)
 	}
 
+	dm.list.Title = view.RenderFacets(dm.query, len(infos), len(dm.all), dm.filter, dm.order)
 	return dm.list.SetItems(items)
 }
 
+// cycle steps through options, starting and ending with none of them.
+func cycle(options []string, current string) string {
+	i := slices.Index(options, current)
+	if i == len(options)-1 {
+		return ""
+	}
+
+	return options[i+1]
+}
+
 func (dm *DictionaryModel) View() string {
 	if len(dm.list.Items()) == 0 {
-		return view.BaseViewStyle.Render("No items found") + view.FootNoteStyle.Render(
-			"ctrl+q: back to search\n",
+		msg := "No items found"
+		if len(dm.all) > 0 {
+			msg = "No result matches the filters"
+		}
+
+		return view.BaseViewStyle.Render(msg) + view.FootNoteStyle.Render(
+			footer(StateDictionaryList),
 		)
 	}
 
-	return "\n" + dm.list.View()
+	return "\n" + dm.list.View() + view.FootNoteStyle.Padding(1, 0, 2, 4).Render(footer(StateDictionaryList))
+}
+
+func (dm *DictionaryModel) Snapshot() tea.Model {
+	c := *dm
+	return &c
+}
+
+func (dm *DictionaryModel) Capturing() bool {
+	return dm.list.SettingFilter()
+}
+
+func (dm *DictionaryModel) Breadcrumb() string {
+	return dm.query
 }
@@ -1,16 +1,33 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+	"io"
 	"reflect"
 )
 
 type Engine struct {
 	state  AppState
 	models map[AppState]tea.Model
-
-	router *TransitionRouter
+	size   *tea.WindowSizeMsg
+
+	router  *TransitionRouter
+	history *History
+
+	// help shows the full key help of the current screen over it, any key closes it.
+	help bool
+	// palette is the open command palette, it takes every key.
+	palette *palette
+	// lookup floats over the current screen while lookingUp, it takes every key.
+	lookup    *LookupModel
+	lookingUp bool
+	// notice replaces the breadcrumbs until the next key.
+	notice string
 }
 
 func NewEngine(
@@ -23,6 +40,10 @@ func NewEngine(
 	translateDetailModel *TranslationDetailModel,
 	explainerModel *ExplainerModel,
 	explainerDetailModel *ExplainerDetailModel,
+	compareModel *CompareModel,
+	kanjiModel *KanjiModel,
+	kanjiDetailModel *KanjiDetailModel,
+	lookupModel *LookupModel,
 ) *Engine {
 	models := map[AppState]tea.Model{
 		StateMenu:            menuModel,
@@ -34,92 +55,257 @@ func NewEngine(
 		StateTranslateDetail: translateDetailModel,
 		StateExplainer:       explainerModel,
 		StateExplainerDetail: explainerDetailModel,
+		StateCompare:         compareModel,
+		StateKanji:           kanjiModel,
+		StateKanjiDetail:     kanjiDetailModel,
 	}
 
-	engine := &Engine{state: StateMenu, models: models, router: &TransitionRouter{
-		handlers: make(map[reflect.Type]TransitionHandler),
-	}}
+	engine := &Engine{state: StateMenu, models: models, history: NewHistory(StateMenu), lookup: lookupModel}
+	engine.router = NewTransitionRouter(engine.transitions()...)
 
-	return engine.registerRouters()
+	return engine
 }
 
-func (e *Engine) registerRouters() *Engine {
-	e.router.Register(switchToMenu{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		return StateMenu, nil
-	})
-
-	e.router.Register(switchToError{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		te := msg.(switchToError)
-		if dm, ok := e.getModel(StateMenu).(*MenuModel); ok {
-			cmd := dm.SetError(te.err)
-			return StateMenu, []tea.Cmd{cmd}
-		}
-
-		return StateMenu, nil
-	})
-
-	e.router.Register(switchToDictionaryNew{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		st := msg.(switchToDictionaryNew)
-		if dm, ok := e.getModel(StateDictionaryList).(*DictionaryModel); ok {
-			cmd := dm.SetItems(st.res)
-			return StateDictionaryList, []tea.Cmd{cmd}
-		}
-
-		return StateDictionaryList, nil
-	})
-
-	e.router.Register(switchToDictionaryOld{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		return StateDictionaryList, nil
-	})
+// transitions is the UI state machine of the engine, see Transition.
+func (e *Engine) transitions() []Transition {
+	table := []Transition{
+		{
+			Msg: switchToMenu{},
+			To:  StateMenu,
+		},
+		{
+			Msg: switchToError{},
+			To:  StateMenu,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if dm, ok := e.getModel(StateMenu).(*MenuModel); ok {
+					return []tea.Cmd{dm.SetError(msg.(switchToError).err)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateMenu, StateDetail},
+			Msg:  switchToSearch{},
+			To:   StateSearch,
+		},
+		{
+			From: []AppState{StateSearch, StateTranslate, StateExplainer, StateKanjiDetail},
+			Msg:  switchToLoading{},
+			To:   StateLoading,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if lm, ok := e.getModel(StateLoading).(*LoadingModel); ok {
+					return []tea.Cmd{lm.Tick()}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateLoading},
+			Msg:  switchToDictionaryNew{},
+			To:   StateDictionaryList,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				st := msg.(switchToDictionaryNew)
+				if dm, ok := e.getModel(StateDictionaryList).(*DictionaryModel); ok {
+					return []tea.Cmd{dm.SetItems(st.query, st.res)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateDictionaryList, StateLoading, StateExplainerDetail},
+			Msg:  switchToDetail{},
+			To:   StateDetail,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if dm, ok := e.getModel(StateDetail).(*DictionaryDetailModel); ok {
+					return []tea.Cmd{dm.SetItem(msg.(switchToDetail).res)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateMenu},
+			Msg:  switchToTranslate{},
+			To:   StateTranslate,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				lang := msg.(switchToTranslate).lang
+				if tm, ok := e.getModel(StateTranslate).(*TranslatorModel); ok && lang != nil {
+					return []tea.Cmd{tm.SetTarget(*lang)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateLoading},
+			Msg:  switchToTranslateDetail{},
+			To:   StateTranslateDetail,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				st := msg.(switchToTranslateDetail)
+				if td, ok := e.getModel(StateTranslateDetail).(*TranslationDetailModel); ok {
+					return []tea.Cmd{td.SetItem(st.input, st.provider, st.res)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateLoading},
+			Msg:  switchToCompare{},
+			To:   StateCompare,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				st := msg.(switchToCompare)
+				if cm, ok := e.getModel(StateCompare).(*CompareModel); ok {
+					return []tea.Cmd{cm.SetItem(st.input, st.lang, st.res)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateMenu},
+			Msg:  switchToExplainer{},
+			To:   StateExplainer,
+		},
+		{
+			From: []AppState{StateLoading},
+			Msg:  switchToExplainerDetail{},
+			To:   StateExplainerDetail,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if td, ok := e.getModel(StateExplainerDetail).(*ExplainerDetailModel); ok {
+					return []tea.Cmd{td.SetItem(msg.(switchToExplainerDetail).res)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateMenu},
+			Msg:  switchToKanji{},
+			To:   StateKanji,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if km, ok := e.getModel(StateKanji).(*KanjiModel); ok {
+					return []tea.Cmd{km.Load()}
+				}
+
+				return nil
+			},
+		},
+		{
+			From: []AppState{StateKanji},
+			Msg:  switchToKanjiDetail{},
+			To:   StateKanjiDetail,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if km, ok := e.getModel(StateKanjiDetail).(*KanjiDetailModel); ok {
+					return []tea.Cmd{km.SetKanji(msg.(switchToKanjiDetail).kanji)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From:  []AppState{StateTranslateDetail, StateDetail, StateKanjiDetail},
+			Msg:   handoff{},
+			Guard: handoffTo(StateExplainer),
+			When:  "to Explain",
+			To:    StateExplainer,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if em, ok := e.getModel(StateExplainer).(*ExplainerModel); ok {
+					return []tea.Cmd{em.Explain(msg.(handoff).text)}
+				}
+
+				return nil
+			},
+		},
+		{
+			From:  []AppState{StateDetail},
+			Msg:   handoff{},
+			Guard: handoffTo(StateTranslate),
+			When:  "to Translate",
+			To:    StateTranslate,
+			Enter: func(msg tea.Msg) []tea.Cmd {
+				if tm, ok := e.getModel(StateTranslate).(*TranslatorModel); ok {
+					return []tea.Cmd{tm.SetInput(msg.(handoff).text)}
+				}
+
+				return nil
+			},
+		},
+	}
 
-	e.router.Register(switchToDetail{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		st := msg.(switchToDetail)
-		if dm, ok := e.getModel(StateDetail).(*DictionaryDetailModel); ok {
-			return StateDetail, []tea.Cmd{dm.SetItem(st.res)}
+	// the palette opens the modes from any screen, each through a launch transition of its own
+	for _, t := range table {
+		switch t.Msg.(type) {
+		case switchToSearch, switchToTranslate, switchToExplainer, switchToKanji:
+			table = append(table, launched(t))
 		}
+	}
 
-		return StateDetail, nil
-	})
-
-	e.router.Register(switchToSearch{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		return StateSearch, nil
-	})
-
-	e.router.Register(switchToTranslate{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		return StateTranslate, nil
-	})
+	return table
+}
 
-	e.router.Register(switchToTranslateDetail{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		st := msg.(switchToTranslateDetail)
-		if td, ok := e.getModel(StateTranslateDetail).(*TranslationDetailModel); ok {
-			return StateTranslateDetail, []tea.Cmd{td.SetItem(st.res)}
-		}
+// launched is the transition opening the destination of t from the palette, on any screen.
+func launched(t Transition) Transition {
+	typ := reflect.TypeOf(t.Msg)
+	return Transition{
+		Msg: launch{},
+		Guard: func(msg tea.Msg) bool {
+			return reflect.TypeOf(msg.(launch).msg) == typ
+		},
+		When: typ.Name(),
+		To:   t.To,
+		Enter: func(msg tea.Msg) []tea.Cmd {
+			if t.Enter == nil {
+				return nil
+			}
+
+			return t.Enter(msg.(launch).msg)
+		},
+	}
+}
 
-		return StateTranslateDetail, nil
-	})
+func handoffTo(s AppState) func(msg tea.Msg) bool {
+	return func(msg tea.Msg) bool {
+		return msg.(handoff).to == s
+	}
+}
 
-	e.router.Register(switchToLoading{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		if lm, ok := e.getModel(StateLoading).(*LoadingModel); ok {
-			return StateLoading, []tea.Cmd{lm.Tick()}
+// Validate checks the transition table against every engine message and state,
+// and that every state has a model to show.
+func (e *Engine) Validate() error {
+	var errs []error
+	for _, s := range appStates {
+		if e.getModel(s) == nil {
+			errs = append(errs, fmt.Errorf("state %s has no model", s))
 		}
+	}
 
-		return StateLoading, nil
-	})
+	errs = append(errs, e.router.Validate(StateMenu, appStates, transitionMessages))
+	return errors.Join(errs...)
+}
 
-	e.router.Register(switchToExplainer{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		return StateExplainer, nil
-	})
+// WriteStateGraph writes the UI flow, as a Graphviz digraph when dot is set, as a table otherwise.
+// It doesn't need any model, so it can run without the API keys.
+func WriteStateGraph(w io.Writer, dot bool) error {
+	e := &Engine{models: make(map[AppState]tea.Model)}
+	r := NewTransitionRouter(e.transitions()...)
+	if dot {
+		return r.Dot(w, appStates)
+	}
 
-	e.router.Register(switchToExplainerDetail{}, func(msg tea.Msg) (AppState, []tea.Cmd) {
-		st := msg.(switchToExplainerDetail)
-		if td, ok := e.getModel(StateExplainerDetail).(*ExplainerDetailModel); ok {
-			return StateExplainerDetail, []tea.Cmd{td.SetItem(st.res)}
-		}
+	if err := r.Table(w); err != nil {
+		return err
+	}
 
-		return StateExplainerDetail, nil
-	})
+	if err := r.Validate(StateMenu, appStates, transitionMessages); err != nil {
+		_, werr := fmt.Fprintf(w, "\n%v\n", err)
+		return werr
+	}
 
-	return e
+	return nil
 }
 
 func (e *Engine) getModel(s AppState) tea.Model {
@@ -142,10 +328,146 @@ func (e *Engine) Init() tea.Cmd {
 	return nil
 }
 
+// navigate records the transition into next in the history. Loading screens are transient
+// and never recorded, going to the menu starts a new history.
+func (e *Engine) navigate(next AppState) {
+	defer func() {
+		e.state = next
+	}()
+
+	switch {
+	case next == StateLoading:
+	case next == StateMenu:
+		e.history.Reset(StateMenu)
+	case next == e.history.Current():
+	default:
+		e.history.Save(e.getModel(e.history.Current()))
+		e.history.Push(next)
+	}
+}
+
+// restore brings an entry of the history back on screen, as it was when it was left.
+func (e *Engine) restore(entry navEntry) {
+	if s, ok := entry.snapshot.(snapshotter); ok {
+		e.setModel(entry.state, s.Snapshot())
+	}
+
+	// the snapshot may have been taken under another theme
+	if t, ok := e.getModel(entry.state).(themer); ok {
+		t.applyTheme()
+	}
+
+	e.state = entry.state
+	if e.size != nil {
+		e.resize(entry.state, *e.size)
+	}
+}
+
+// resize tells a model how much room it has, which is the window minus the breadcrumbs line.
+func (e *Engine) resize(s AppState, msg tea.WindowSizeMsg) tea.Cmd {
+	m := e.getModel(s)
+	if m == nil {
+		return nil
+	}
+
+	msg.Height--
+	mdl, cmd := m.Update(msg)
+	e.setModel(s, mdl)
+	return cmd
+}
+
+// broadcastSize resizes every model, not only the one on screen, so the others are laid out
+// for the current window when they are shown.
+func (e *Engine) broadcastSize(msg tea.WindowSizeMsg) tea.Cmd {
+	e.size = &msg
+	footerWidth = max(msg.Width-view.PaddingLeftTwo*2, 0)
+
+	cmds := make([]tea.Cmd, 0, len(e.models))
+	for s := range e.models {
+		cmds = append(cmds, e.resize(s, msg))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (e *Engine) back() {
+	if e.state == StateLoading {
+		return
+	}
+
+	e.history.Save(e.getModel(e.state))
+	if entry, ok := e.history.Back(); ok {
+		e.restore(entry)
+	}
+}
+
+func (e *Engine) forward() {
+	if e.state == StateLoading {
+		return
+	}
+
+	e.history.Save(e.getModel(e.state))
+	if entry, ok := e.history.Forward(); ok {
+		e.restore(entry)
+	}
+}
+
 func (e *Engine) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if h, ok := e.router.Handle(msg); ok {
-		nextState, cmds := h(msg)
-		e.state = nextState
+	switch msg.(type) {
+	case navigateBack:
+		e.back()
+		return e, nil
+
+	case navigateForward:
+		e.forward()
+		return e, nil
+
+	case toggleTheme:
+		view.ApplyTheme(view.NextTheme())
+		e.notice = "Theme: " + view.ActiveTheme().Name
+		for _, m := range e.models {
+			if t, ok := m.(themer); ok {
+				t.applyTheme()
+			}
+		}
+		if e.size != nil {
+			// the models render with the styles of the moment, resizing renders them again
+			return e, e.broadcastSize(*e.size)
+		}
+		return e, nil
+
+	case notify:
+		e.notice = msg.(notify).text
+		return e, nil
+
+	case datasetOpened:
+		if err := msg.(datasetOpened).err; err != nil {
+			e.notice = "Dataset not loaded: " + err.Error()
+		}
+		if e.size != nil {
+			// laying the screens out again renders them with the dataset
+			return e, e.broadcastSize(*e.size)
+		}
+		return e, nil
+
+	case openLookup:
+		e.lookingUp = true
+		return e, e.lookup.Open()
+
+	case lookedUp:
+		_, cmd := e.lookup.Update(msg)
+		return e, cmd
+	}
+
+	if t, ok := e.router.Handle(e.state, msg); ok {
+		var cmds []tea.Cmd
+		if t.Enter != nil {
+			cmds = t.Enter(msg)
+		}
+
+		// a notice belongs to the screen it was given on
+		e.notice = ""
+		e.navigate(t.To)
 		if len(cmds) > 0 {
 			return e, tea.Batch(cmds...)
 		}
@@ -154,10 +476,59 @@ func (e *Engine) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch m := msg.(type) {
+	case tea.WindowSizeMsg:
+		return e, e.broadcastSize(m)
+
+	case tea.MouseMsg:
+		// the overlays don't take the mouse, the screen below them shouldn't either
+		if e.palette != nil || e.lookingUp || e.help {
+			return e, nil
+		}
+
 	case tea.KeyMsg:
-		switch m.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		e.notice = ""
+
+		if e.palette != nil {
+			open, cmd := e.palette.Update(m)
+			if !open {
+				e.palette = nil
+			}
+			return e, cmd
+		}
+
+		if e.lookingUp {
+			var cmd tea.Cmd
+			e.lookingUp, cmd = e.lookup.Update(m)
+			return e, cmd
+		}
+
+		if c, ok := e.getModel(e.state).(capturer); ok && c.Capturing() {
+			break
+		}
+
+		switch {
+		case key.Matches(m, keys.Quit):
 			return e, tea.Quit
+
+		case e.help:
+			e.help = false
+			return e, nil
+
+		case key.Matches(m, keys.Help) && !(textEntry[e.state] && m.Type == tea.KeyRunes):
+			e.help = true
+			return e, nil
+
+		case key.Matches(m, keys.Palette) && e.state != StateLoading:
+			e.palette = newPalette(e.commands())
+			return e, nil
+
+		case key.Matches(m, keys.Lookup):
+			e.lookingUp = true
+			return e, e.lookup.Open()
+
+		case key.Matches(m, keys.Forward):
+			e.forward()
+			return e, nil
 		}
 	}
 
@@ -173,9 +544,79 @@ func (e *Engine) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (e *Engine) View() string {
-	if m := e.getModel(e.state); m != nil {
-		return m.View()
+	var width, height int
+	if e.size != nil {
+		width, height = e.size.Width, e.size.Height
+	}
+
+	if e.palette != nil {
+		return e.palette.View(width, height)
+	}
+
+	if e.help {
+		return view.RenderOverlay(e.state.String()+" keys", newHelp().FullHelpView(screenHelp(e.state).FullHelp()), width, height)
+	}
+
+	m := e.getModel(e.state)
+	if m == nil {
+		return fmt.Sprintf("Unknown state: %v", e.state)
+	}
+
+	if e.lookingUp {
+		return view.CenterOverlay(e.lookup.View(), m.View()+e.breadcrumbs(), width, height)
+	}
+
+	return m.View() + e.breadcrumbs()
+}
+
+// breadcrumbs labels every entry by the model it shows: the live one for the current entry,
+// the snapshot for the others.
+func (e *Engine) breadcrumbs() string {
+	if e.state == StateLoading {
+		return ""
+	}
+
+	if e.notice != "" {
+		return "\n" + view.MutedStyle.PaddingLeft(view.PaddingLeftTwo).Render(e.notice)
+	}
+
+	crumbs := e.history.Breadcrumbs(func(entry navEntry, current bool) string {
+		m := entry.snapshot
+		if current {
+			m = e.getModel(entry.state)
+		}
+
+		if b, ok := m.(breadcrumber); ok {
+			if label := b.Breadcrumb(); label != "" {
+				return ansi.Truncate(label, 16, "…")
+			}
+		}
+
+		return entry.state.String()
+	})
+
+	return "\n" + view.MutedStyle.PaddingLeft(view.PaddingLeftTwo).Render(crumbs)
+}
+
+// commands gathers the palette actions of the engine and of every model, in screen order.
+func (e *Engine) commands() []command {
+	commands := []command{
+		{title: "Main menu", run: dispatch(switchToMenu{})},
+		{title: "Back", run: dispatch(navigateBack{})},
+		{title: "Forward", run: dispatch(navigateForward{})},
+		{title: "Toggle theme", run: dispatch(toggleTheme{})},
+		{title: "Look up a word…", run: dispatch(openLookup{})},
+	}
+
+	for _, s := range appStates {
+		if l, ok := e.getModel(s).(launcher); ok {
+			commands = append(commands, l.Launchers()...)
+		}
+	}
+
+	if c, ok := e.getModel(e.state).(commander); ok {
+		commands = append(commands, c.Commands()...)
 	}
 
-	return fmt.Sprintf("Unknown state: %v", e.state)
+	return append(commands, command{title: "Quit", run: tea.Quit})
 }
@@ -1,26 +1,31 @@
 package engine
 
 import (
-	"github.com/charmbracelet/bubbles/viewport"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/view"
 	"strings"
 )
 
 type ExplainerDetailModel struct {
-	viewport viewport.Model
+	viewport pager
+	gloss    table.Model
+	glossTop int // content line the gloss table starts on
 
 	exp *domain.Explanation
+	sc  domain.Searcher
 }
 
-func NewExplainerDetailModel() *ExplainerDetailModel {
-	vp := viewport.New(80, 20)
-	vp.Style = view.BorderStyle.PaddingRight(2)
-
+func NewExplainerDetailModel(searcher domain.Searcher) *ExplainerDetailModel {
 	return &ExplainerDetailModel{
-		viewport: vp,
+		viewport: newPager(80, 20),
+		gloss:    table.New(table.WithStyles(view.GlossTableStyles(false))),
 		exp:      nil,
+		sc:       searcher,
 	}
 }
 
@@ -28,22 +33,95 @@ func (edm *ExplainerDetailModel) Init() tea.Cmd {
 	return nil
 }
 
+// lookupCmd searches the dictionary for a token of the explanation and opens the best entry,
+// preferring one written or read exactly like the token. A failed search stays on the
+// explanation and says so.
+func (edm *ExplainerDetailModel) lookupCmd(token string) tea.Cmd {
+	return tea.Batch(
+		func() tea.Msg {
+			return notify{fmt.Sprintf("Looking up %s…", token)}
+		},
+		func() tea.Msg {
+			res, err := edm.sc.Search(token)
+			if err != nil {
+				return notify{fmt.Sprintf("Lookup of %s failed: %v", token, err)}
+			}
+
+			best := domain.BestMatch(res, token)
+			if best == nil {
+				return notify{fmt.Sprintf("No dictionary entry for %s", token)}
+			}
+
+			return switchToDetail{res: best}
+		},
+	)
+}
+
 func (edm *ExplainerDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		edm.viewport.Width = msg.Width
+		edm.viewport.Height = max(msg.Height-view.FooterHeight-1, view.MinViewportHeight) // pager status line
+		edm.resizeGloss()
+		edm.render()
+		return edm, nil
+
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if token, ok := edm.tokenAt(msg.Y); ok {
+				return edm, edm.lookupCmd(token)
+			}
+		}
+
+		return edm, edm.viewport.Update(msg)
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlQ:
+		if edm.viewport.Searching() {
+			return edm, edm.viewport.Update(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.Back):
 			return edm, func() tea.Msg {
-				return switchToExplainer{}
+				return navigateBack{}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return edm, tea.Quit
 
+		case key.Matches(msg, keys.Focus):
+			if len(edm.gloss.Rows()) == 0 {
+				return edm, nil
+			}
+
+			if edm.gloss.Focused() {
+				edm.gloss.Blur()
+			} else {
+				edm.gloss.Focus()
+			}
+
+			edm.gloss.SetStyles(view.GlossTableStyles(edm.gloss.Focused()))
+			edm.render()
+			return edm, nil
+
+		case key.Matches(msg, keys.Select):
+			row := edm.gloss.SelectedRow()
+			if !edm.gloss.Focused() || len(row) == 0 || row[0] == "" {
+				return edm, nil
+			}
+
+			return edm, edm.lookupCmd(row[0])
+
 		default:
-			edm.viewport, cmd = edm.viewport.Update(msg)
+			if edm.gloss.Focused() && !edm.scrollsPager(msg) {
+				edm.gloss, cmd = edm.gloss.Update(msg)
+				edm.render()
+				return edm, cmd
+			}
+
+			cmd = edm.viewport.Update(msg)
 			return edm, cmd
 		}
 	}
@@ -51,33 +129,127 @@ func (edm *ExplainerDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return edm, cmd
 }
 
+// scrollsPager reports whether a key goes to the pager even while the gloss table has the focus:
+// the paging keys and the half-page scrolls.
+func (edm *ExplainerDetailModel) scrollsPager(msg tea.KeyMsg) bool {
+	for _, b := range keys.paging() {
+		if key.Matches(msg, *b) {
+			return true
+		}
+	}
+
+	return key.Matches(msg, edm.viewport.KeyMap.HalfPageDown, edm.viewport.KeyMap.HalfPageUp)
+}
+
 func (edm *ExplainerDetailModel) View() string {
-	fn := view.FootNoteStyle.Padding(1, 0, 2, 4).Render(
-		" ↑/k up • ↓/j down • ctrl+q: back to the explainer\n",
-	)
+	fn := view.FootNoteStyle.Padding(1, 0, 2, 4).Render(footer(StateExplainerDetail))
 
-	if edm.viewport.View() == "" {
+	if edm.exp == nil {
 		return "No explanation" + fn
 	}
 
 	return edm.viewport.View() + fn
 }
 
-func (edm *ExplainerDetailModel) SetItem(explanation *domain.Explanation) tea.Cmd {
-	edm.exp = explanation
-
+// render rebuilds the viewport content around the gloss table, keeping the scroll position.
+func (edm *ExplainerDetailModel) render() {
 	if edm.exp == nil {
 		edm.viewport.SetContent("")
-		return nil
+		return
 	}
 
 	var b strings.Builder
 	core, analysis, usage := view.RenderExplainer(edm.exp)
+	wrap := lipgloss.NewStyle().Width(edm.contentWidth())
 
-	b.WriteString(core.Render() + "\n")
-	b.WriteString(analysis.Render() + "\n")
-	b.WriteString(usage.Render() + "\n")
+	b.WriteString(wrap.Render(core.Render()) + "\n")
+	b.WriteString(view.WordStyleBold.Render("Gloss Analysis:") + "\n\n")
+	edm.glossTop = strings.Count(b.String(), "\n")
+	b.WriteString(edm.gloss.View() + "\n\n")
+	b.WriteString(wrap.Render(analysis.RenderGrammar()) + "\n")
+	b.WriteString(wrap.Render(usage.Render()) + "\n")
 
 	edm.viewport.SetContent(b.String())
+}
+
+func (edm *ExplainerDetailModel) applyTheme() {
+	edm.viewport.applyTheme()
+	edm.gloss.SetStyles(view.GlossTableStyles(edm.gloss.Focused()))
+}
+
+// tokenAt is the token of the gloss row drawn on row y of the view, the row gets selected.
+func (edm *ExplainerDetailModel) tokenAt(y int) (string, bool) {
+	line, ok := edm.viewport.LineAt(y)
+	if !ok {
+		return "", false
+	}
+
+	i := line - edm.glossTop - 2 // header and its border
+	rows := edm.gloss.Rows()
+	if i < 0 || i >= len(rows) || rows[i][0] == "" {
+		return "", false
+	}
+
+	edm.gloss.SetCursor(i)
+	edm.render()
+	return rows[i][0], true
+}
+
+func (edm *ExplainerDetailModel) contentWidth() int {
+	return edm.viewport.Width - edm.viewport.Style.GetHorizontalFrameSize()
+}
+
+// resizeGloss gives the meaning column whatever width the other columns leave.
+func (edm *ExplainerDetailModel) resizeGloss() {
+	cols := edm.gloss.Columns()
+	if len(cols) == 0 {
+		return
+	}
+
+	rest := 0
+	for _, c := range cols[:len(cols)-1] {
+		rest += c.Width + 2 // cell padding
+	}
+
+	cols[len(cols)-1].Width = max(edm.contentWidth()-rest-2, 12)
+	edm.gloss.SetColumns(cols)
+	edm.gloss.SetWidth(edm.contentWidth())
+}
+
+func (edm *ExplainerDetailModel) SetItem(explanation *domain.Explanation) tea.Cmd {
+	edm.exp = explanation
+
+	edm.gloss.Blur()
+	edm.gloss.SetStyles(view.GlossTableStyles(false))
+	if edm.exp != nil {
+		_, analysis, _ := view.RenderExplainer(edm.exp)
+		cols, rows := analysis.GlossTable()
+		edm.gloss.SetRows(nil)
+		edm.gloss.SetColumns(cols)
+		edm.gloss.SetRows(rows)
+		edm.gloss.SetHeight(len(rows) + 2) // header and its border
+		edm.gloss.SetCursor(0)
+		edm.resizeGloss()
+	}
+
+	edm.render()
+	edm.viewport.GotoTop()
 	return nil
 }
+
+func (edm *ExplainerDetailModel) Capturing() bool {
+	return edm.viewport.Searching()
+}
+
+func (edm *ExplainerDetailModel) Snapshot() tea.Model {
+	c := *edm
+	return &c
+}
+
+func (edm *ExplainerDetailModel) Breadcrumb() string {
+	if edm.exp == nil {
+		return ""
+	}
+
+	return edm.exp.Original
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
@@ -33,12 +34,16 @@ func (em *ExplainerModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+func (em *ExplainerModel) Launchers() []command {
+	return []command{{title: "Explain…", run: dispatch(launch{switchToExplainer{}})}}
+}
+
 func (em *ExplainerModel) View() string {
 	return view.LesterViewStyle.Render(fmt.Sprintf(
 		"Insert Japanese Sentence to get the explanation: \n\n%s",
 		em.ti.View(),
 	)) + view.LesterViewNoteStyle.Render(
-		"esc/ctrl+c: exit • ctrl+q: back to menu • enter: ask\n",
+		footer(StateExplainer),
 	)
 }
 
@@ -64,9 +69,13 @@ func (em *ExplainerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		em.ti.Width = max(msg.Width-view.PaddingLeftTwo*2, view.DefaultTextWidth)
+		return em, nil
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
+		switch {
+		case key.Matches(msg, keys.Submit):
 			query := em.ti.Value()
 			if query == "" {
 				return em, nil
@@ -75,13 +84,13 @@ func (em *ExplainerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			em.ti.Reset()
 			return em, em.askCmd(context.Background(), query)
 
-		case tea.KeyCtrlQ:
+		case key.Matches(msg, keys.Back):
 			em.ti.Reset()
 			return em, func() tea.Msg {
-				return switchToMenu{}
+				return navigateBack{}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return em, tea.Quit
 		}
 	}
@@ -93,3 +102,9 @@ func (em *ExplainerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (em *ExplainerModel) Focus() tea.Cmd {
 	return em.ti.Focus()
 }
+
+// Explain asks about text handed over from another mode.
+func (em *ExplainerModel) Explain(text string) tea.Cmd {
+	em.ti.Reset()
+	return em.askCmd(context.Background(), text)
+}
@@ -0,0 +1,105 @@
+package engine
+
+import "github.com/ziliscite/dictionary-cli/internal/view"
+
+// grid is a cursor over the items of a view.RenderGrid, label cells are skipped.
+type grid struct {
+	cells   []view.GridCell
+	items   []int // cell of every item
+	columns int
+	cursor  int // item
+}
+
+// newGrid lays out cells in rows of columns, keeping the cursor on the same item when it can.
+func newGrid(cells []view.GridCell, columns, cursor int) grid {
+	g := grid{cells: cells, columns: max(columns, 1)}
+	for i, c := range cells {
+		if c.Label == "" {
+			g.items = append(g.items, i)
+		}
+	}
+
+	g.cursor = max(min(cursor, len(g.items)-1), 0)
+	return g
+}
+
+func (g *grid) rows() int {
+	return (len(g.cells) + g.columns - 1) / g.columns
+}
+
+// row is the row of the cursor.
+func (g *grid) row() int {
+	if len(g.items) == 0 {
+		return 0
+	}
+
+	return g.items[g.cursor] / g.columns
+}
+
+// move steps the cursor by items, or by rows to the item closest to the same column.
+func (g *grid) move(items, rows int) {
+	if len(g.items) == 0 {
+		return
+	}
+
+	if items != 0 {
+		g.cursor = max(min(g.cursor+items, len(g.items)-1), 0)
+		return
+	}
+
+	cell := g.items[g.cursor]
+	row, col := cell/g.columns+rows, cell%g.columns
+	if row < 0 || row >= g.rows() {
+		return
+	}
+
+	best, dist := -1, g.columns
+	for i, c := range g.items {
+		if c/g.columns != row {
+			continue
+		}
+
+		if d := abs(c%g.columns - col); d < dist {
+			best, dist = i, d
+		}
+	}
+
+	if best >= 0 {
+		g.cursor = best
+	}
+}
+
+// itemAt is the item drawn at row and column, false for labels and blanks.
+func (g *grid) itemAt(row, col int) (int, bool) {
+	if row < 0 || col < 0 || col >= g.columns {
+		return 0, false
+	}
+
+	cell := row*g.columns + col
+	for i, c := range g.items {
+		if c == cell {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// view renders rows of the grid from first, with the cursor shown when focused.
+func (g *grid) view(first, rows int, focused bool) string {
+	cells := g.cells
+	if focused && len(g.items) > 0 {
+		cells = append([]view.GridCell(nil), g.cells...)
+		cells[g.items[g.cursor]].Cursor = true
+	}
+
+	return view.RenderGrid(cells, g.columns, first, rows)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
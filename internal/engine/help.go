@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+)
+
+// screenHelp is the help.KeyMap of a screen, generated from the bindings it listens to so the
+// footers and the help overlay can't drift from the behaviour.
+type screenHelp AppState
+
+// ShortHelp leads with help, the footer is cut to the window and the rest is in the overlay.
+func (s screenHelp) ShortHelp() []key.Binding {
+	return append(append([]key.Binding{keys.Help}, s.bindings()...), keys.Quit)
+}
+
+func (s screenHelp) FullHelp() [][]key.Binding {
+	columns := [][]key.Binding{s.bindings()}
+	if scroll := scrolling(AppState(s)); len(scroll) > 0 {
+		columns = append(columns, scroll)
+	}
+
+	return append(columns, []key.Binding{keys.Forward, keys.Palette, keys.Lookup, keys.Help, keys.Quit})
+}
+
+func (s screenHelp) bindings() []key.Binding {
+	scope := keys.scopes()[AppState(s)]
+
+	bindings := make([]key.Binding, len(scope))
+	for i, b := range scope {
+		bindings[i] = *b
+	}
+
+	return bindings
+}
+
+// scrolling are the bindings of the bubbles a screen forwards its keys to.
+func scrolling(s AppState) []key.Binding {
+	switch s {
+	case StateDetail, StateTranslateDetail, StateExplainerDetail, StateKanjiDetail:
+		vp := viewport.DefaultKeyMap()
+		return []key.Binding{vp.Up, vp.Down, vp.PageUp, vp.PageDown, vp.HalfPageUp, vp.HalfPageDown}
+
+	case StateDictionaryList:
+		l := list.DefaultKeyMap()
+		return []key.Binding{l.CursorUp, l.CursorDown, l.PrevPage, l.NextPage, l.GoToStart, l.GoToEnd}
+	}
+
+	return nil
+}
+
+// newHelp is a help bubble styled with the active theme.
+func newHelp() help.Model {
+	h := help.New()
+	h.Styles = view.HelpStyles()
+	return h
+}
+
+// footerWidth is the room the footers have, kept up to date by the engine.
+var footerWidth int
+
+// footer renders the short help of a screen.
+func footer(s AppState) string {
+	h := newHelp()
+	h.Width = footerWidth
+	return h.ShortHelpView(screenHelp(s).ShortHelp()) + "\n"
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+	"strings"
+)
+
+// maxBreadcrumbs is how many of the latest history entries the footer shows.
+const maxBreadcrumbs = 5
+
+// snapshotter is implemented by models that can be restored as they were when navigating through the history.
+type snapshotter interface {
+	Snapshot() tea.Model
+}
+
+// breadcrumber is implemented by models that describe their content better than their state name.
+type breadcrumber interface {
+	Breadcrumb() string
+}
+
+type navEntry struct {
+	state    AppState
+	snapshot tea.Model
+}
+
+// History is the navigation stack of the engine, cursor points at the entry on screen.
+type History struct {
+	entries []navEntry
+	cursor  int
+}
+
+func NewHistory(root AppState) *History {
+	return &History{
+		entries: []navEntry{{state: root}},
+		cursor:  0,
+	}
+}
+
+func (h *History) Current() AppState {
+	return h.entries[h.cursor].state
+}
+
+// Push records a new entry after the current one, dropping anything that was forward of it.
+func (h *History) Push(s AppState) {
+	h.entries = append(h.entries[:h.cursor+1], navEntry{state: s})
+	h.cursor++
+}
+
+// Reset collapses the stack into a single root entry.
+func (h *History) Reset(root AppState) {
+	h.entries = []navEntry{{state: root}}
+	h.cursor = 0
+}
+
+// Save keeps a snapshot of the model of the current entry, so it comes back as it was left.
+func (h *History) Save(m tea.Model) {
+	if s, ok := m.(snapshotter); ok {
+		h.entries[h.cursor].snapshot = s.Snapshot()
+	}
+}
+
+// Back moves the cursor one entry back and returns it; ok is false at the root.
+func (h *History) Back() (navEntry, bool) {
+	if h.cursor == 0 {
+		return navEntry{}, false
+	}
+
+	h.cursor--
+	return h.entries[h.cursor], true
+}
+
+// Forward moves the cursor one entry forward and returns it; ok is false at the newest entry.
+func (h *History) Forward() (navEntry, bool) {
+	if h.cursor >= len(h.entries)-1 {
+		return navEntry{}, false
+	}
+
+	h.cursor++
+	return h.entries[h.cursor], true
+}
+
+// Breadcrumbs renders the path up to the current entry, labelled by the models' breadcrumbs.
+func (h *History) Breadcrumbs(label func(entry navEntry, current bool) string) string {
+	start := max(0, h.cursor+1-maxBreadcrumbs)
+
+	crumbs := make([]string, 0, maxBreadcrumbs+1)
+	if start > 0 {
+		crumbs = append(crumbs, "…")
+	}
+
+	for i := start; i <= h.cursor; i++ {
+		c := label(h.entries[i], i == h.cursor)
+		if i == h.cursor {
+			c = view.WordStyle.Render(c)
+		}
+
+		crumbs = append(crumbs, c)
+	}
+
+	return strings.Join(crumbs, view.MutedStyle.Render(" › "))
+}
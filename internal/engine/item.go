@@ -11,6 +11,8 @@ func (i item) FilterValue() string {
 	var b strings.Builder
 
 	for _, v := range i.Japanese {
+		b.WriteString(v.Word)
+		b.WriteString(" ")
 		b.WriteString(v.Reading)
 		b.WriteString(" ")
 	}
@@ -26,5 +28,5 @@ func (i item) FilterValue() string {
 		}
 	}
 
-	return i.Slug + b.String()
+	return i.Slug + " " + b.String()
 }
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+)
+
+// KanjiDetailModel shows a kanji picked in the kanji lookup, and searches the words using it.
+type KanjiDetailModel struct {
+	viewport pager
+
+	kanji rune
+	kd    *domain.Lazy[domain.Kanjidic]
+	rads  *domain.Lazy[domain.Radicals]
+	sc    domain.Searcher
+}
+
+func NewKanjiDetailModel(kd *domain.Lazy[domain.Kanjidic], radicals *domain.Lazy[domain.Radicals], searcher domain.Searcher) *KanjiDetailModel {
+	return &KanjiDetailModel{
+		viewport: newPager(78, 12),
+		kd:       kd,
+		rads:     radicals,
+		sc:       searcher,
+	}
+}
+
+func (kdm *KanjiDetailModel) Init() tea.Cmd {
+	return nil
+}
+
+// wordsCmd searches the dictionary for the kanji and lists the entries written with it.
+func (kdm *KanjiDetailModel) wordsCmd() tea.Cmd {
+	k := kdm.kanji
+	return tea.Batch(
+		func() tea.Msg {
+			return switchToLoading{}
+		},
+		func() tea.Msg {
+			res, err := kdm.sc.Search(string(k))
+			if err != nil {
+				return switchToError{err}
+			}
+
+			return switchToDictionaryNew{query: string(k), res: domain.ContainingKanji(res, k)}
+		},
+	)
+}
+
+func (kdm *KanjiDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		kdm.viewport.Width = msg.Width
+		kdm.viewport.Height = max(msg.Height-view.FooterHeight-1, view.MinViewportHeight) // pager status line
+		if kdm.kanji == 0 {
+			return kdm, nil
+		}
+
+		return kdm, kdm.render()
+
+	case tea.MouseMsg:
+		return kdm, kdm.viewport.Update(msg)
+
+	case tea.KeyMsg:
+		if kdm.viewport.Searching() {
+			return kdm, kdm.viewport.Update(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.Back):
+			return kdm, func() tea.Msg {
+				return navigateBack{}
+			}
+
+		case key.Matches(msg, keys.KanjiWords):
+			if kdm.kanji == 0 {
+				return kdm, nil
+			}
+
+			return kdm, kdm.wordsCmd()
+
+		case key.Matches(msg, keys.Explain):
+			if kdm.kanji == 0 {
+				return kdm, nil
+			}
+
+			return kdm, func() tea.Msg {
+				return handoff{to: StateExplainer, text: string(kdm.kanji)}
+			}
+
+		case key.Matches(msg, keys.Quit):
+			return kdm, tea.Quit
+
+		default:
+			return kdm, kdm.viewport.Update(msg)
+		}
+	}
+
+	return kdm, nil
+}
+
+func (kdm *KanjiDetailModel) View() string {
+	return kdm.viewport.View() + view.FootNoteStyle.Render(
+		footer(StateKanjiDetail),
+	)
+}
+
+func (kdm *KanjiDetailModel) SetKanji(k rune) tea.Cmd {
+	kdm.kanji = k
+
+	cmd := kdm.render()
+	kdm.viewport.GotoTop()
+	return cmd
+}
+
+// render runs the kanji through glamour, wrapping to the current viewport width.
+func (kdm *KanjiDetailModel) render() tea.Cmd {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStyles(view.GlamourStyle()),
+		glamour.WithWordWrap(kdm.viewport.Width-kdm.viewport.Style.GetHorizontalFrameSize()-2),
+		glamour.WithColorProfile(view.ColorProfile()),
+	)
+	if err != nil {
+		return func() tea.Msg {
+			return switchToError{err}
+		}
+	}
+
+	kd, openKanji := dataset(kdm.kd)
+	rads, openRadicals := dataset(kdm.rads)
+
+	k, known := kd.Lookup(kdm.kanji)
+	str, err := renderer.Render(view.RenderKanjiEntry(kdm.kanji, k, known, rads.Components(kdm.kanji)))
+	if err != nil {
+		return func() tea.Msg {
+			return switchToError{err}
+		}
+	}
+
+	kdm.viewport.SetContent(str)
+	return tea.Batch(openKanji, openRadicals)
+}
+
+func (kdm *KanjiDetailModel) Commands() []command {
+	if kdm.kanji == 0 {
+		return nil
+	}
+
+	return []command{
+		{title: fmt.Sprintf("Words using %c", kdm.kanji), run: kdm.wordsCmd()},
+		{title: fmt.Sprintf("Explain %c", kdm.kanji), run: dispatch(handoff{to: StateExplainer, text: string(kdm.kanji)})},
+	}
+}
+
+func (kdm *KanjiDetailModel) Capturing() bool {
+	return kdm.viewport.Searching()
+}
+
+func (kdm *KanjiDetailModel) applyTheme() {
+	kdm.viewport.applyTheme()
+}
+
+func (kdm *KanjiDetailModel) Snapshot() tea.Model {
+	c := *kdm
+	return &c
+}
+
+func (kdm *KanjiDetailModel) Breadcrumb() string {
+	if kdm.kanji == 0 {
+		return ""
+	}
+
+	return string(kdm.kanji)
+}
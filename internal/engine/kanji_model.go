@@ -0,0 +1,339 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// kanjiGridTop is the row of the view the radical grid starts on, under the padding and the title.
+const kanjiGridTop = 3
+
+// KanjiModel finds a kanji from its radicals: picking radicals in the grid narrows the candidates
+// down to the kanji containing all of them.
+type KanjiModel struct {
+	radicals *domain.Lazy[domain.Radicals]
+	kanji    *domain.Lazy[domain.Kanjidic]
+
+	strokes  int // only radicals of this many strokes are listed, 0 lists them all
+	selected []rune
+
+	radicalGrid   grid
+	candidateGrid grid
+	onCandidates  bool
+
+	width  int
+	height int
+}
+
+func NewKanjiModel(radicals *domain.Lazy[domain.Radicals], kanji *domain.Lazy[domain.Kanjidic]) *KanjiModel {
+	return &KanjiModel{
+		radicals: radicals,
+		kanji:    kanji,
+		width:    80,
+		height:   24,
+	}
+}
+
+// Load opens RADKFILE and KANJIDIC2 in the background the first time the kanji lookup is shown,
+// the grids are laid out once they are.
+func (km *KanjiModel) Load() tea.Cmd {
+	_, _, open := km.data()
+	km.refresh()
+	return open
+}
+
+// data are the radicals and the kanji, or empty ones with the commands opening them while they
+// aren't ready.
+func (km *KanjiModel) data() (*domain.Radicals, *domain.Kanjidic, tea.Cmd) {
+	radicals, openRadicals := dataset(km.radicals)
+	kanji, openKanji := dataset(km.kanji)
+	if openRadicals != nil || openKanji != nil {
+		return radicals, kanji, tea.Batch(openRadicals, openKanji)
+	}
+
+	return radicals, kanji, nil
+}
+
+func (km *KanjiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (km *KanjiModel) Launchers() []command {
+	return []command{{title: "Kanji lookup…", run: dispatch(launch{switchToKanji{}})}}
+}
+
+func (km *KanjiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		km.width, km.height = msg.Width, msg.Height
+		km.refresh()
+		return km, nil
+
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return km, nil
+		}
+
+		return km, km.click(msg.X, msg.Y)
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Back):
+			return km, func() tea.Msg {
+				return navigateBack{}
+			}
+
+		case key.Matches(msg, keys.Quit):
+			return km, tea.Quit
+
+		case key.Matches(msg, keys.Select):
+			if km.onCandidates {
+				return km, km.open()
+			}
+
+			km.toggle()
+
+		case key.Matches(msg, keys.Focus):
+			km.onCandidates = !km.onCandidates && len(km.candidateGrid.items) > 0
+
+		case key.Matches(msg, keys.GridUp):
+			km.focused().move(0, -1)
+
+		case key.Matches(msg, keys.GridDown):
+			km.focused().move(0, 1)
+
+		case key.Matches(msg, keys.GridLeft):
+			km.focused().move(-1, 0)
+
+		case key.Matches(msg, keys.GridRight):
+			km.focused().move(1, 0)
+
+		case key.Matches(msg, keys.FewerStrokes):
+			km.strokes = max(km.strokes-1, 0)
+			km.refresh()
+
+		case key.Matches(msg, keys.MoreStrokes):
+			km.strokes = min(km.strokes+1, km.maxStrokes())
+			km.refresh()
+
+		case key.Matches(msg, keys.ClearFilters):
+			km.selected = nil
+			km.onCandidates = false
+			km.refresh()
+		}
+	}
+
+	return km, nil
+}
+
+func (km *KanjiModel) focused() *grid {
+	if km.onCandidates {
+		return &km.candidateGrid
+	}
+
+	return &km.radicalGrid
+}
+
+// listed are the radicals of the grid, after the stroke filter.
+func (km *KanjiModel) listed() []domain.Radical {
+	radicals, _, _ := km.data()
+	return slices.DeleteFunc(slices.Clone(radicals.List()), func(r domain.Radical) bool {
+		return km.strokes > 0 && r.Strokes != km.strokes
+	})
+}
+
+func (km *KanjiModel) maxStrokes() int {
+	radicals, _, _ := km.data()
+
+	var n int
+	for _, r := range radicals.List() {
+		n = max(n, r.Strokes)
+	}
+
+	return n
+}
+
+// refresh lays out both grids again after the selection, the filter or the size changed.
+func (km *KanjiModel) refresh() {
+	radicals, kanji, open := km.data()
+	if open != nil {
+		return
+	}
+
+	columns := max((km.width-view.PaddingLeftTwo-view.PaddingLeftOne)/view.GridCellWidth, 1)
+
+	candidates := radicals.Candidates(km.selected)
+	kanji.Sort(candidates)
+	present := radicals.Present(candidates)
+
+	var cells []view.GridCell
+	var last int
+	for _, r := range km.listed() {
+		if km.strokes == 0 && r.Strokes != last {
+			cells = append(cells, view.GridCell{Label: strconv.Itoa(r.Strokes)})
+			last = r.Strokes
+		}
+
+		selected := slices.Contains(km.selected, r.Literal)
+		cells = append(cells, view.GridCell{
+			Char:     r.Literal,
+			Selected: selected,
+			Disabled: len(km.selected) > 0 && !selected && !present[r.Literal],
+		})
+	}
+	km.radicalGrid = newGrid(cells, columns, km.radicalGrid.cursor)
+
+	cells = make([]view.GridCell, len(candidates))
+	for i, k := range candidates {
+		cells[i] = view.GridCell{Char: k}
+	}
+	km.candidateGrid = newGrid(cells, columns, km.candidateGrid.cursor)
+
+	if len(candidates) == 0 {
+		km.onCandidates = false
+	}
+}
+
+// toggle adds the radical under the cursor to the selection, or takes it out.
+func (km *KanjiModel) toggle() {
+	g := km.radicalGrid
+	if len(g.items) == 0 {
+		return
+	}
+
+	cell := g.cells[g.items[g.cursor]]
+	if cell.Disabled {
+		return
+	}
+
+	if i := slices.Index(km.selected, cell.Char); i >= 0 {
+		km.selected = slices.Delete(km.selected, i, i+1)
+	} else {
+		km.selected = append(km.selected, cell.Char)
+	}
+
+	km.candidateGrid.cursor = 0
+	km.refresh()
+}
+
+// open shows the candidate under the cursor.
+func (km *KanjiModel) open() tea.Cmd {
+	g := km.candidateGrid
+	if len(g.items) == 0 {
+		return nil
+	}
+
+	k := g.cells[g.items[g.cursor]].Char
+	return func() tea.Msg {
+		return switchToKanjiDetail{kanji: k}
+	}
+}
+
+// candidateRows is how many rows of candidates fit under the radicals.
+func (km *KanjiModel) candidateRows() int {
+	return max(km.height-view.FooterHeight-kanjiGridTop-km.radicalRows()-3, 1)
+}
+
+// radicalRows is the height of the radical grid, or of the line standing in for an empty one.
+func (km *KanjiModel) radicalRows() int {
+	return max(km.radicalGrid.rows(), 1)
+}
+
+// candidateTop is the first candidate row shown, so the cursor stays in sight.
+func (km *KanjiModel) candidateTop() int {
+	return max(km.candidateGrid.row()-km.candidateRows()+1, 0)
+}
+
+// click toggles a radical, or highlights a candidate and opens it when it already was.
+func (km *KanjiModel) click(x, y int) tea.Cmd {
+	col := (x - view.PaddingLeftTwo) / view.GridCellWidth
+	if x < view.PaddingLeftTwo {
+		return nil
+	}
+
+	if i, ok := km.radicalGrid.itemAt(y-kanjiGridTop, col); ok {
+		km.radicalGrid.cursor = i
+		km.onCandidates = false
+		km.toggle()
+		return nil
+	}
+
+	top := kanjiGridTop + km.radicalRows() + 2 // blank line and the candidates title
+	i, ok := km.candidateGrid.itemAt(y-top+km.candidateTop(), col)
+	if !ok || y < top {
+		return nil
+	}
+
+	if km.onCandidates && i == km.candidateGrid.cursor {
+		return km.open()
+	}
+
+	km.candidateGrid.cursor = i
+	km.onCandidates = true
+	return nil
+}
+
+func (km *KanjiModel) View() string {
+	radicals, _, open := km.data()
+	if open != nil {
+		return view.BaseViewStyle.Render("Loading the radicals…") +
+			view.LesterViewNoteStyle.Render(footer(StateKanji))
+	}
+
+	if len(radicals.List()) == 0 {
+		return view.BaseViewStyle.Render("No RADKFILE is installed, see the README to look kanji up by radical.") +
+			view.LesterViewNoteStyle.Render(footer(StateKanji))
+	}
+
+	strokes := "all strokes"
+	if km.strokes > 0 {
+		strokes = fmt.Sprintf("%d strokes", km.strokes)
+	}
+
+	title := "Pick the radicals of the kanji " + view.MutedStyle.Render("· "+strokes)
+	if len(km.selected) > 0 {
+		title += view.MutedStyle.Render(" · ") + string(km.selected)
+	}
+
+	grid := km.radicalGrid.view(0, km.radicalGrid.rows(), !km.onCandidates)
+	if len(km.radicalGrid.items) == 0 {
+		grid = view.MutedStyle.Render(fmt.Sprintf("No radical has %d strokes", km.strokes))
+	}
+
+	var b strings.Builder
+	b.WriteString(title + "\n\n")
+	b.WriteString(grid + "\n\n")
+
+	switch n := len(km.candidateGrid.items); {
+	case len(km.selected) == 0:
+		b.WriteString(view.MutedStyle.Render("Candidates show up once a radical is picked"))
+	case n == 0:
+		b.WriteString(view.MutedStyle.Render("No kanji has all of these radicals"))
+	default:
+		b.WriteString(fmt.Sprintf("%d candidates\n", n))
+		b.WriteString(km.candidateGrid.view(km.candidateTop(), km.candidateRows(), km.onCandidates))
+	}
+
+	return view.BaseViewStyle.Padding(1, 0, 0, view.PaddingLeftTwo).Render(b.String()) + "\n" +
+		view.LesterViewNoteStyle.PaddingLeft(view.PaddingLeftTwo).Render(footer(StateKanji))
+}
+
+func (km *KanjiModel) Snapshot() tea.Model {
+	c := *km
+	c.selected = slices.Clone(km.selected)
+	return &c
+}
+
+func (km *KanjiModel) Breadcrumb() string {
+	if len(km.selected) == 0 {
+		return ""
+	}
+
+	return "Kanji " + string(km.selected)
+}
@@ -0,0 +1,321 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyMap is every key binding of the application. Global bindings are handled by the engine
+// on every screen, the others by the models listed in scopes.
+type KeyMap struct {
+	Quit    key.Binding
+	Back    key.Binding
+	Forward key.Binding
+	Help    key.Binding
+	Palette key.Binding
+	Lookup  key.Binding
+
+	Up        key.Binding
+	Down      key.Binding
+	Select    key.Binding
+	WipeError key.Binding
+
+	Submit    key.Binding
+	NewSearch key.Binding
+
+	Next    key.Binding
+	Prev    key.Binding
+	Focus   key.Binding
+	NextTab key.Binding
+	PrevTab key.Binding
+
+	Translate     key.Binding
+	Compare       key.Binding
+	Explain       key.Binding
+	BackTranslate key.Binding
+	ToggleLang    key.Binding
+	PrevLang      key.Binding
+	NextLang      key.Binding
+	PrevProvider  key.Binding
+	NextProvider  key.Binding
+
+	CopyItem key.Binding
+	CopyAll  key.Binding
+
+	ToggleCommon key.Binding
+	CycleJLPT    key.Binding
+	CyclePOS     key.Binding
+	ToggleKanji  key.Binding
+	CycleSort    key.Binding
+	ClearFilters key.Binding
+
+	Top       key.Binding
+	Bottom    key.Binding
+	Find      key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+
+	GridUp       key.Binding
+	GridDown     key.Binding
+	GridLeft     key.Binding
+	GridRight    key.Binding
+	FewerStrokes key.Binding
+	MoreStrokes  key.Binding
+	KanjiWords   key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:    key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "exit")),
+		Back:    key.NewBinding(key.WithKeys("esc", "alt+left"), key.WithHelp("esc", "back")),
+		Forward: key.NewBinding(key.WithKeys("alt+right"), key.WithHelp("alt+right", "forward")),
+		Help:    key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?/f1", "help")),
+		Palette: key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "commands")),
+		Lookup:  key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "quick lookup")),
+
+		Up:        key.NewBinding(key.WithKeys("up", "left"), key.WithHelp("up", "previous")),
+		Down:      key.NewBinding(key.WithKeys("down", "right"), key.WithHelp("down", "next")),
+		Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "choose")),
+		WipeError: key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "wipe error")),
+
+		Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
+		NewSearch: key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "new search")),
+
+		Next:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next")),
+		Prev:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous")),
+		Focus: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch focus")),
+
+		NextTab: key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next tab")),
+		PrevTab: key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous tab")),
+
+		Translate:     key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "translate")),
+		Compare:       key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "compare providers")),
+		Explain:       key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "explain")),
+		BackTranslate: key.NewBinding(key.WithKeys("ctrl+b"), key.WithHelp("ctrl+b", "back-translate check")),
+		ToggleLang:    key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "toggle language")),
+		PrevLang:      key.NewBinding(key.WithKeys("shift+left"), key.WithHelp("shift+left", "previous language")),
+		NextLang:      key.NewBinding(key.WithKeys("shift+right"), key.WithHelp("shift+right", "next language")),
+		PrevProvider:  key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+up", "previous provider")),
+		NextProvider:  key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+down", "next provider")),
+
+		CopyItem: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy paragraph")),
+		CopyAll:  key.NewBinding(key.WithKeys("Y"), key.WithHelp("Y", "copy all")),
+
+		ToggleCommon: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "common only")),
+		CycleJLPT:    key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "JLPT level")),
+		CyclePOS:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "part of speech")),
+		ToggleKanji:  key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "written with kanji")),
+		CycleSort:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
+		ClearFilters: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "clear filters")),
+
+		Top:       key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("gg", "top")),
+		Bottom:    key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("G", "bottom")),
+		Find:      key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		NextMatch: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+		PrevMatch: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "previous match")),
+
+		GridUp:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("up/k", "up")),
+		GridDown:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("down/j", "down")),
+		GridLeft:     key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("left/h", "left")),
+		GridRight:    key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("right/l", "right")),
+		FewerStrokes: key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "fewer strokes")),
+		MoreStrokes:  key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+", "more strokes")),
+		KanjiWords:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "words using it")),
+	}
+}
+
+// keys is the key map every model matches against, see UseKeyMap.
+var keys = DefaultKeyMap()
+
+// UseKeyMap replaces the key map, it must be called before the models are created.
+func UseKeyMap(k KeyMap) {
+	keys = k
+}
+
+// named maps the names used in the key map file to the bindings.
+func (k *KeyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":           &k.Quit,
+		"back":           &k.Back,
+		"forward":        &k.Forward,
+		"help":           &k.Help,
+		"palette":        &k.Palette,
+		"lookup":         &k.Lookup,
+		"up":             &k.Up,
+		"down":           &k.Down,
+		"select":         &k.Select,
+		"wipe_error":     &k.WipeError,
+		"submit":         &k.Submit,
+		"new_search":     &k.NewSearch,
+		"next":           &k.Next,
+		"prev":           &k.Prev,
+		"focus":          &k.Focus,
+		"next_tab":       &k.NextTab,
+		"prev_tab":       &k.PrevTab,
+		"translate":      &k.Translate,
+		"compare":        &k.Compare,
+		"explain":        &k.Explain,
+		"back_translate": &k.BackTranslate,
+		"toggle_lang":    &k.ToggleLang,
+		"prev_lang":      &k.PrevLang,
+		"next_lang":      &k.NextLang,
+		"prev_provider":  &k.PrevProvider,
+		"next_provider":  &k.NextProvider,
+		"copy_item":      &k.CopyItem,
+		"copy_all":       &k.CopyAll,
+		"toggle_common":  &k.ToggleCommon,
+		"cycle_jlpt":     &k.CycleJLPT,
+		"cycle_pos":      &k.CyclePOS,
+		"toggle_kanji":   &k.ToggleKanji,
+		"cycle_sort":     &k.CycleSort,
+		"clear_filters":  &k.ClearFilters,
+		"top":            &k.Top,
+		"bottom":         &k.Bottom,
+		"find":           &k.Find,
+		"next_match":     &k.NextMatch,
+		"prev_match":     &k.PrevMatch,
+		"grid_up":        &k.GridUp,
+		"grid_down":      &k.GridDown,
+		"grid_left":      &k.GridLeft,
+		"grid_right":     &k.GridRight,
+		"fewer_strokes":  &k.FewerStrokes,
+		"more_strokes":   &k.MoreStrokes,
+		"kanji_words":    &k.KanjiWords,
+	}
+}
+
+// LoadKeyMap reads a JSON object of binding names to keys, e.g. {"back": ["ctrl+g"]},
+// on top of the default key map. A missing file keeps the defaults.
+func LoadKeyMap(path string) (KeyMap, error) {
+	k := DefaultKeyMap()
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return k, fmt.Errorf("read key map: %w", err)
+	}
+
+	var overrides map[string][]string
+	if err = json.Unmarshal(b, &overrides); err != nil {
+		return k, fmt.Errorf("decode key map %s: %w", path, err)
+	}
+
+	named := k.named()
+	for name, ks := range overrides {
+		binding, ok := named[name]
+		if !ok {
+			return k, fmt.Errorf("unknown binding %q in %s", name, path)
+		}
+
+		if len(ks) == 0 {
+			return k, fmt.Errorf("binding %q in %s has no keys", name, path)
+		}
+
+		binding.SetKeys(ks...)
+		binding.SetHelp(strings.Join(ks, "/"), binding.Help().Desc)
+	}
+
+	return k, nil
+}
+
+// global are the bindings the engine handles before any model.
+func (k *KeyMap) global() []*key.Binding {
+	return []*key.Binding{&k.Quit, &k.Forward, &k.Help, &k.Palette, &k.Lookup}
+}
+
+// scopes lists the bindings every screen listens to, on top of the global ones.
+func (k *KeyMap) scopes() map[AppState][]*key.Binding {
+	return map[AppState][]*key.Binding{
+		StateMenu:            {&k.Up, &k.Down, &k.Select, &k.WipeError},
+		StateSearch:          {&k.Back, &k.Submit},
+		StateLoading:         {},
+		StateDictionaryList:  {&k.Back, &k.Select, &k.Find, &k.ToggleCommon, &k.CycleJLPT, &k.CyclePOS, &k.ToggleKanji, &k.CycleSort, &k.ClearFilters},
+		StateDetail:          append([]*key.Binding{&k.Back, &k.NewSearch, &k.NextTab, &k.PrevTab, &k.Next, &k.Prev, &k.Translate, &k.Explain}, k.paging()...),
+		StateTranslate:       {&k.Back, &k.Translate, &k.Compare, &k.ToggleLang, &k.PrevLang, &k.NextLang, &k.PrevProvider, &k.NextProvider},
+		StateTranslateDetail: append([]*key.Binding{&k.Back, &k.Next, &k.Prev, &k.CopyItem, &k.CopyAll, &k.Explain, &k.BackTranslate}, k.paging()...),
+		StateExplainer:       {&k.Back, &k.Submit},
+		StateExplainerDetail: append([]*key.Binding{&k.Back, &k.Focus, &k.Select}, k.paging()...),
+		StateCompare:         {&k.Back, &k.Explain},
+		StateKanji:           {&k.Back, &k.Select, &k.Focus, &k.GridUp, &k.GridDown, &k.GridLeft, &k.GridRight, &k.FewerStrokes, &k.MoreStrokes, &k.ClearFilters},
+		StateKanjiDetail:     append([]*key.Binding{&k.Back, &k.KanjiWords, &k.Explain}, k.paging()...),
+	}
+}
+
+// paging are the bindings of the pager on the detail screens.
+func (k *KeyMap) paging() []*key.Binding {
+	return []*key.Binding{&k.Top, &k.Bottom, &k.Find, &k.NextMatch, &k.PrevMatch}
+}
+
+// textEntry are the screens where printable keys are typed into an input.
+var textEntry = map[AppState]bool{
+	StateSearch:    true,
+	StateExplainer: true,
+	StateTranslate: true,
+}
+
+// bubbleKeys are the keys the bubbles of a screen handle on their own: the scrolling keys, and
+// the horizontal scroll of the viewport, which the help leaves out. The gloss table's keys are
+// the viewport's.
+func bubbleKeys(s AppState) []key.Binding {
+	bindings := scrolling(s)
+	switch s {
+	case StateDetail, StateTranslateDetail, StateExplainerDetail, StateKanjiDetail:
+		vp := viewport.DefaultKeyMap()
+		bindings = append(bindings, vp.Left, vp.Right)
+	}
+
+	return bindings
+}
+
+// Validate reports keys bound twice on the same screen, keys shadowing one the screen's bubbles
+// handle, and printable keys bound on screens where they should be typed instead. Help is exempt
+// from the last, it only answers its other keys there.
+func (k *KeyMap) Validate() error {
+	names := make(map[*key.Binding]string)
+	for name, b := range k.named() {
+		names[b] = name
+	}
+
+	var errs []error
+	for _, s := range appStates {
+		builtin := make(map[string]string)
+		for _, b := range bubbleKeys(s) {
+			for _, ks := range b.Keys() {
+				builtin[ks] = b.Help().Desc
+			}
+		}
+
+		owners := make(map[string]string)
+		for _, b := range append(k.global(), k.scopes()[s]...) {
+			for _, ks := range b.Keys() {
+				if other, ok := owners[ks]; ok && other != names[b] {
+					errs = append(errs, fmt.Errorf("%s: %q is bound to both %s and %s", s, ks, other, names[b]))
+				}
+				owners[ks] = names[b]
+
+				if desc, ok := builtin[ks]; ok {
+					errs = append(errs, fmt.Errorf("%s: %q of %s shadows the built-in %q", s, ks, names[b], desc))
+				}
+
+				if textEntry[s] && b != &k.Help && utf8.RuneCountInString(ks) == 1 {
+					errs = append(errs, fmt.Errorf("%s: %q of %s can't be typed anymore", s, ks, names[b]))
+				}
+			}
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errors.Join(errs...)
+}
@@ -2,9 +2,9 @@ package engine
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/view"
 )
 
@@ -14,12 +14,18 @@ type LoadingModel struct {
 
 func NewLoadingModel() *LoadingModel {
 	sp := spinner.New()
-	sp.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("70"))
 	sp.Spinner = spinner.Points
 
-	return &LoadingModel{
+	lm := &LoadingModel{
 		sp: sp,
 	}
+
+	lm.applyTheme()
+	return lm
+}
+
+func (lm *LoadingModel) applyTheme() {
+	lm.sp.Style = view.SpinnerStyle
 }
 
 func (lm *LoadingModel) Init() tea.Cmd {
@@ -33,8 +39,8 @@ func (lm *LoadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		lm.sp, cmd = lm.sp.Update(msg)
 		return lm, cmd
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		switch {
+		case key.Matches(msg, keys.Quit):
 			return lm, tea.Quit
 		}
 	}
@@ -48,6 +54,6 @@ func (lm *LoadingModel) Tick() tea.Cmd {
 
 func (lm *LoadingModel) View() string {
 	return view.LesterViewStyle.Render(fmt.Sprintf("Now loading %s", lm.sp.View())) + view.LesterViewNoteStyle.Render(
-		"ctrl+c/esc: exit\n",
+		footer(StateLoading),
 	)
 }
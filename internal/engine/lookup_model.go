@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+)
+
+// lookedUp carries the result of a quick lookup, it is routed to the overlay whatever the screen.
+type lookedUp struct {
+	query  string
+	res    *domain.Information
+	others int
+	err    error
+}
+
+// openLookup opens the quick lookup overlay.
+type openLookup struct{}
+
+// LookupModel is the quick lookup overlay: a dictionary search summarized over the current
+// screen, which is left untouched.
+type LookupModel struct {
+	ti textinput.Model
+	sc domain.Searcher
+
+	query   string
+	res     *domain.Information
+	others  int
+	err     error
+	loading bool
+}
+
+func NewLookupModel(searcher domain.Searcher) *LookupModel {
+	ti := textinput.New()
+	ti.Prompt = "Look up: "
+	ti.Placeholder = "word"
+	ti.CharLimit = 60
+	ti.Width = view.LookupWidth - len(ti.Prompt)
+
+	return &LookupModel{
+		ti: ti,
+		sc: searcher,
+	}
+}
+
+// Open clears the previous lookup and focuses the input.
+func (lm *LookupModel) Open() tea.Cmd {
+	lm.ti.Reset()
+	lm.query, lm.res, lm.others, lm.err, lm.loading = "", nil, 0, nil, false
+	return lm.ti.Focus()
+}
+
+func (lm *LookupModel) lookupCmd(query string) tea.Cmd {
+	return func() tea.Msg {
+		res, err := lm.sc.Search(query)
+		if err != nil {
+			return lookedUp{query: query, err: err}
+		}
+
+		return lookedUp{query: query, res: domain.BestMatch(res, query), others: max(len(res)-1, 0)}
+	}
+}
+
+// Update reports whether the overlay stays open.
+func (lm *LookupModel) Update(msg tea.Msg) (bool, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case lookedUp:
+		// an answer to an earlier query is dropped
+		if msg.query != lm.query {
+			return true, nil
+		}
+
+		lm.loading = false
+		lm.res, lm.others, lm.err = msg.res, msg.others, msg.err
+		return true, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, closeOverlay, keys.Lookup):
+			lm.ti.Blur()
+			return false, nil
+
+		case msg.Type == tea.KeyCtrlC:
+			return false, tea.Quit
+
+		case key.Matches(msg, keys.Submit):
+			// the same word is looked up again only after it failed
+			query := lm.ti.Value()
+			if query == "" || query == lm.query && (lm.loading || lm.err == nil) {
+				return true, nil
+			}
+
+			lm.query, lm.loading, lm.err = query, true, nil
+			return true, lm.lookupCmd(query)
+		}
+	}
+
+	lm.ti, cmd = lm.ti.Update(msg)
+	return true, cmd
+}
+
+func (lm *LookupModel) View() string {
+	body := lm.ti.View()
+
+	switch {
+	case lm.loading:
+		body += "\n\n" + view.MutedStyle.Render("Looking up "+lm.query+"...")
+	case lm.err != nil:
+		body += "\n\n" + view.MutedStyle.Render("Lookup failed: "+lm.err.Error())
+	case lm.query != "" && lm.res == nil:
+		body += "\n\n" + view.MutedStyle.Render(fmt.Sprintf("No entry for %q", lm.query))
+	case lm.res != nil:
+		body += "\n\n" + view.RenderLookup(lm.res, lm.others)
+	}
+
+	body += "\n\n" + newHelp().ShortHelpView([]key.Binding{keys.Submit, closeOverlay})
+
+	return view.BorderStyle.Padding(0, 1).Width(view.LookupWidth + 2).Render(body)
+}
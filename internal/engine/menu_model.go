@@ -2,6 +2,8 @@ package engine
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/view"
 	"strings"
 
@@ -14,10 +16,11 @@ const (
 	Search Choice = iota
 	Translate
 	Explain
+	Kanji
 )
 
 func (c Choice) String() string {
-	if c < Search || c > Explain {
+	if c < Search || c > Kanji {
 		return "Invalid"
 	}
 
@@ -25,9 +28,12 @@ func (c Choice) String() string {
 		"Search",
 		"Translate",
 		"Explain",
+		"Kanji lookup",
 	}[c]
 }
 
+const menuTitle = "What do you want to do?"
+
 type MenuModel struct {
 	Choice  int
 	Choices []Choice
@@ -38,7 +44,7 @@ type MenuModel struct {
 func NewMenuModel() *MenuModel {
 	return &MenuModel{
 		Choices: []Choice{
-			Search, Translate, Explain,
+			Search, Translate, Explain, Kanji,
 		},
 	}
 }
@@ -49,42 +55,45 @@ func (m *MenuModel) Init() tea.Cmd {
 
 func (m *MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+
+		// a click highlights a choice, a click on the highlighted one opens it
+		i, ok := m.choiceAt(msg.Y)
+		if !ok {
+			return m, nil
+		}
+
+		if i == m.Choice {
+			return m, m.choose()
+		}
+
+		m.Choice = i
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		switch {
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 
-		case tea.KeyCtrlF:
+		case key.Matches(msg, keys.WipeError):
 			return m, func() tea.Msg {
 				m.err = nil
 				return nil
 			}
 
-		case tea.KeyEnter:
-			switch m.Choices[m.Choice] {
-			case Search:
-				return m, func() tea.Msg {
-					return switchToSearch{}
-				}
-
-			case Translate:
-				return m, func() tea.Msg {
-					return switchToTranslate{}
-				}
-
-			case Explain:
-				return m, func() tea.Msg {
-					return switchToExplainer{}
-				}
-			}
+		case key.Matches(msg, keys.Select):
+			return m, m.choose()
 
-		case tea.KeyDown, tea.KeyRight:
+		case key.Matches(msg, keys.Down):
 			m.Choice++
 			if m.Choice > len(m.Choices)-1 {
 				m.Choice = 0
 			}
 
-		case tea.KeyUp, tea.KeyLeft:
+		case key.Matches(msg, keys.Up):
 			m.Choice--
 			if m.Choice < 0 {
 				m.Choice = len(m.Choices) - 1
@@ -98,10 +107,43 @@ func (m *MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// choose opens the highlighted mode.
+func (m *MenuModel) choose() tea.Cmd {
+	switch m.Choices[m.Choice] {
+	case Search:
+		return func() tea.Msg {
+			return switchToSearch{}
+		}
+
+	case Translate:
+		return func() tea.Msg {
+			return switchToTranslate{}
+		}
+
+	case Explain:
+		return func() tea.Msg {
+			return switchToExplainer{}
+		}
+
+	case Kanji:
+		return func() tea.Msg {
+			return switchToKanji{}
+		}
+	}
+
+	return nil
+}
+
+// choiceAt is the choice drawn on row y of the view.
+func (m *MenuModel) choiceAt(y int) (int, bool) {
+	i := y - view.LesterViewStyle.GetPaddingTop() - lipgloss.Height(menuTitle) - 1 // blank line
+	return i, m.err == nil && i >= 0 && i < len(m.Choices)
+}
+
 func (m *MenuModel) View() string {
 	if m.err != nil {
 		return view.BaseViewStyle.Render(m.err.Error()) + view.LesterViewNoteStyle.Render(
-			"esc/ctrl+c: exit • ctrl+f: wipe error\n",
+			footer(StateMenu),
 		)
 	}
 
@@ -113,10 +155,10 @@ func (m *MenuModel) View() string {
 	choices := strings.Join(lines, "\n")
 
 	return view.LesterViewStyle.Render(fmt.Sprintf(
-		"What do you want to do?\n\n%s\n",
-		choices,
+		"%s\n\n%s\n",
+		menuTitle, choices,
 	)) + view.LesterViewNoteStyle.Render(
-		"esc/ctrl+c: exit • enter: choose • up/down: select\n",
+		footer(StateMenu),
 	)
 }
 
@@ -0,0 +1,256 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+	"strings"
+	"unicode"
+)
+
+// capturer is implemented by models that take every key for a while, global bindings included.
+type capturer interface {
+	Capturing() bool
+}
+
+// match is one occurrence of the search query in the pager content.
+type match struct {
+	line, col int
+}
+
+// pager is a viewport with vim motions and an incremental search highlighting its content.
+// It always renders one status line below the viewport.
+type pager struct {
+	viewport.Model
+
+	content string
+	input   textinput.Model
+	query   string
+	matches []match
+	current int
+
+	searching bool
+	origin    int  // offset when the search started, the first match is looked for from there
+	pending   bool // the first key of a doubled motion like gg
+}
+
+func newPager(width, height int) pager {
+	ti := textinput.New()
+	ti.Prompt = "/"
+
+	p := pager{
+		Model: viewport.New(width, height),
+		input: ti,
+	}
+
+	p.applyTheme()
+	return p
+}
+
+func (p *pager) applyTheme() {
+	p.Style = view.BorderStyle.PaddingRight(2)
+}
+
+// Searching reports whether the search query is being typed, every key belongs to the pager then.
+func (p *pager) Searching() bool {
+	return p.searching
+}
+
+func (p *pager) SetContent(s string) {
+	p.content = s
+	p.highlight()
+}
+
+func (p *pager) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	k, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if p.searching {
+			p.input, cmd = p.input.Update(msg)
+			return cmd
+		}
+
+		p.Model, cmd = p.Model.Update(msg)
+		return cmd
+	}
+
+	if p.searching {
+		return p.updateSearch(k)
+	}
+
+	pending := p.pending
+	p.pending = false
+
+	switch {
+	case key.Matches(k, keys.Top):
+		// a single letter is doubled, as in vim
+		if k.Type == tea.KeyRunes && !pending {
+			p.pending = true
+			return nil
+		}
+
+		p.GotoTop()
+
+	case key.Matches(k, keys.Bottom):
+		p.GotoBottom()
+
+	case key.Matches(k, keys.Find):
+		p.searching = true
+		p.origin = p.YOffset
+		p.input.SetValue("")
+		return p.input.Focus()
+
+	case key.Matches(k, keys.NextMatch):
+		p.jump(p.current + 1)
+
+	case key.Matches(k, keys.PrevMatch):
+		p.jump(p.current - 1)
+
+	default:
+		p.Model, cmd = p.Model.Update(msg)
+	}
+
+	return cmd
+}
+
+func (p *pager) updateSearch(k tea.KeyMsg) tea.Cmd {
+	switch k.Type {
+	case tea.KeyEnter:
+		p.searching = false
+		p.input.Blur()
+		return nil
+
+	case tea.KeyEsc:
+		p.searching = false
+		p.input.Blur()
+		p.query = ""
+		p.highlight()
+		p.SetYOffset(p.origin)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(k)
+
+	if p.input.Value() != p.query {
+		p.query = p.input.Value()
+		p.current = 0
+		p.highlight()
+
+		for i, m := range p.matches {
+			if m.line >= p.origin {
+				p.jump(i)
+				break
+			}
+		}
+	}
+
+	return cmd
+}
+
+// LineAt is the content line drawn on row y of the view, rows are relative to the pager.
+func (p *pager) LineAt(y int) (int, bool) {
+	row := y - p.Style.GetBorderTopSize() - p.Style.GetPaddingTop()
+	inner := p.Height - p.Style.GetVerticalFrameSize()
+	if row < 0 || row >= inner {
+		return 0, false
+	}
+
+	return p.YOffset + row, true
+}
+
+// jump makes the i-th match, wrapping around, the current one and scrolls it into view.
+func (p *pager) jump(i int) {
+	if len(p.matches) == 0 {
+		return
+	}
+
+	p.current = (i%len(p.matches) + len(p.matches)) % len(p.matches)
+	p.highlight()
+
+	line := p.matches[p.current].line
+	if line < p.YOffset || line >= p.YOffset+p.Height-p.Style.GetVerticalFrameSize() {
+		p.SetYOffset(line - p.Height/2)
+	}
+}
+
+// highlight marks every occurrence of the query, ignoring case unless it has capitals.
+func (p *pager) highlight() {
+	p.matches = nil
+	if p.query == "" {
+		p.Model.SetContent(p.content)
+		return
+	}
+
+	query := p.query
+	fold := strings.IndexFunc(query, unicode.IsUpper) < 0
+	if fold {
+		query = strings.ToLower(query)
+	}
+
+	lines := strings.Split(p.content, "\n")
+	plains := make([]string, len(lines))
+	for i, line := range lines {
+		plains[i] = ansi.Strip(line)
+
+		haystack := plains[i]
+		if lower := strings.ToLower(haystack); fold && len(lower) == len(haystack) {
+			haystack = lower
+		}
+
+		for from := 0; ; {
+			at := strings.Index(haystack[from:], query)
+			if at < 0 {
+				break
+			}
+
+			p.matches = append(p.matches, match{line: i, col: from + at})
+			from += at + len(query)
+		}
+	}
+
+	if p.current >= len(p.matches) {
+		p.current = 0
+	}
+
+	for first := 0; first < len(p.matches); {
+		line := p.matches[first].line
+
+		var starts []int
+		last := first
+		for ; last < len(p.matches) && p.matches[last].line == line; last++ {
+			starts = append(starts, p.matches[last].col)
+		}
+
+		current := -1
+		if p.current >= first && p.current < last {
+			current = p.current - first
+		}
+
+		lines[line] = view.MarkMatches(plains[line], starts, len(query), current)
+		first = last
+	}
+
+	p.Model.SetContent(strings.Join(lines, "\n"))
+}
+
+// View adds the status line: the query while it is typed, then the position among the matches.
+func (p pager) View() string {
+	status := ""
+	switch {
+	case p.searching:
+		status = p.input.View()
+	case p.query != "" && len(p.matches) == 0:
+		status = view.MutedStyle.Render(fmt.Sprintf("/%s: no match", p.query))
+	case p.query != "":
+		status = view.MutedStyle.Render(fmt.Sprintf("/%s: %d/%d", p.query, p.current+1, len(p.matches)))
+	}
+
+	return p.Model.View() + "\n" + lipgloss.NewStyle().PaddingLeft(view.PaddingLeftOne).Render(status)
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+	"github.com/ziliscite/dictionary-cli/internal/view"
+)
+
+// maxPaletteItems is how many matching commands the palette lists.
+const maxPaletteItems = 8
+
+// closeOverlay closes the palette and the quick lookup, esc quits everywhere else.
+var closeOverlay = key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close"))
+
+// command is an action of the command palette, run dispatches it like any other model command.
+type command struct {
+	title string
+	run   tea.Cmd
+}
+
+// commander is implemented by models that offer actions in the command palette while they are
+// the current screen.
+type commander interface {
+	Commands() []command
+}
+
+// launcher is implemented by models the palette opens from any screen.
+type launcher interface {
+	Launchers() []command
+}
+
+// toggleTheme switches to the next built-in theme.
+type toggleTheme struct{}
+
+// themer is implemented by models keeping copies of the theme's styles in their bubbles,
+// they take them again from view when the theme changes.
+type themer interface {
+	applyTheme()
+}
+
+// notify shows a one-off message in place of the breadcrumbs until the next key.
+type notify struct {
+	text string
+}
+
+func dispatch(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// palette fuzzy-matches the commands gathered when it is opened.
+type palette struct {
+	input    textinput.Model
+	commands []command
+	matches  fuzzy.Matches
+	cursor   int
+}
+
+func newPalette(commands []command) *palette {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Type a command"
+	ti.Focus()
+
+	p := &palette{input: ti, commands: commands}
+	p.filter()
+
+	return p
+}
+
+func (p *palette) String(i int) string {
+	return p.commands[i].title
+}
+
+func (p *palette) Len() int {
+	return len(p.commands)
+}
+
+// filter keeps the commands matching the input, all of them in order while it is empty.
+func (p *palette) filter() {
+	p.cursor = 0
+	if p.input.Value() != "" {
+		p.matches = fuzzy.FindFrom(p.input.Value(), p)
+		return
+	}
+
+	p.matches = make(fuzzy.Matches, len(p.commands))
+	for i, c := range p.commands {
+		p.matches[i] = fuzzy.Match{Str: c.title, Index: i}
+	}
+}
+
+// Update reports whether the palette stays open and the command to run.
+func (p *palette) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if key.Matches(msg, closeOverlay) {
+		return false, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return false, tea.Quit
+
+	case tea.KeyEnter:
+		if len(p.matches) == 0 {
+			return true, nil
+		}
+
+		return false, p.commands[p.matches[p.cursor].Index].run
+
+	case tea.KeyUp:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return true, nil
+
+	case tea.KeyDown:
+		if p.cursor < min(len(p.matches), maxPaletteItems)-1 {
+			p.cursor++
+		}
+		return true, nil
+	}
+
+	value := p.input.Value()
+	p.input, _ = p.input.Update(msg)
+	if p.input.Value() != value {
+		p.filter()
+	}
+
+	return true, nil
+}
+
+func (p *palette) View(width, height int) string {
+	items := make([]view.PaletteItem, 0, maxPaletteItems)
+	for _, m := range p.matches[:min(len(p.matches), maxPaletteItems)] {
+		items = append(items, view.PaletteItem{Title: m.Str, Matched: m.MatchedIndexes})
+	}
+
+	return view.RenderOverlay("Commands", view.RenderPalette(p.input.View(), items, p.cursor), width, height)
+}
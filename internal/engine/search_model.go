@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
@@ -33,12 +34,16 @@ func (im *SearchModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+func (im *SearchModel) Launchers() []command {
+	return []command{{title: "Search…", run: dispatch(launch{switchToSearch{}})}}
+}
+
 func (im *SearchModel) View() string {
 	return view.LesterViewStyle.Render(fmt.Sprintf(
 		"What do you want to know?\n\n%s",
 		im.ti.View(),
 	)) + view.LesterViewNoteStyle.Render(
-		"esc/ctrl+c: exit • ctrl+q: back to menu • enter: search\n",
+		footer(StateSearch),
 	)
 }
 
@@ -53,7 +58,7 @@ func (im *SearchModel) searchCmd(query string) tea.Cmd {
 				return switchToError{err}
 			}
 
-			return switchToDictionaryNew{res: res}
+			return switchToDictionaryNew{query: query, res: res}
 		},
 	)
 }
@@ -62,9 +67,13 @@ func (im *SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		im.ti.Width = max(msg.Width-view.PaddingLeftTwo*2, view.DefaultTextWidth)
+		return im, nil
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyEnter:
+		switch {
+		case key.Matches(msg, keys.Submit):
 			query := im.ti.Value()
 			if query == "" {
 				return im, nil
@@ -73,13 +82,13 @@ func (im *SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			im.ti.Reset()
 			return im, im.searchCmd(query)
 
-		case tea.KeyCtrlQ:
+		case key.Matches(msg, keys.Back):
 			im.ti.Reset()
 			return im, func() tea.Msg {
-				return switchToMenu{}
+				return navigateBack{}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return im, tea.Quit
 		}
 	}
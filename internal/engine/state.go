@@ -1,6 +1,7 @@
 package engine
 
 import (
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 )
 
@@ -16,13 +17,52 @@ const (
 	StateTranslateDetail
 	StateExplainer
 	StateExplainerDetail
+	StateCompare
+	StateKanji
+	StateKanjiDetail
 )
 
+var appStates = []AppState{
+	StateMenu,
+	StateSearch,
+	StateLoading,
+	StateDictionaryList,
+	StateDetail,
+	StateTranslate,
+	StateTranslateDetail,
+	StateExplainer,
+	StateExplainerDetail,
+	StateCompare,
+	StateKanji,
+	StateKanjiDetail,
+}
+
+func (s AppState) String() string {
+	if s < StateMenu || s > StateKanjiDetail {
+		return "Unknown"
+	}
+
+	return [...]string{
+		"Menu",
+		"Search",
+		"Loading",
+		"Results",
+		"Entry",
+		"Translate",
+		"Translation",
+		"Explain",
+		"Explanation",
+		"Compare",
+		"Kanji",
+		"Kanji entry",
+	}[s]
+}
+
 type switchToSearch struct{}
 type switchToDictionaryNew struct {
-	res []domain.Information
+	query string
+	res   []domain.Information
 }
-type switchToDictionaryOld struct{}
 type switchToDetail struct {
 	res *domain.Information
 }
@@ -30,12 +70,62 @@ type switchToLoading struct{}
 type switchToError struct {
 	err error
 }
-type switchToTranslate struct{}
+type switchToTranslate struct {
+	lang *domain.TargetLang // preselected target language, if any
+}
 type switchToTranslateDetail struct {
-	res []domain.Translation
+	input    string
+	provider string
+	res      []domain.TranslationGroup
 }
 type switchToMenu struct{}
 type switchToExplainer struct{}
 type switchToExplainerDetail struct {
 	res *domain.Explanation
 }
+type switchToCompare struct {
+	input string
+	lang  domain.TargetLang
+	res   []domain.Comparison
+}
+
+type switchToKanji struct{}
+type switchToKanjiDetail struct {
+	kanji rune
+}
+
+// handoff carries a piece of text from one mode into another, e.g. a translation into the explainer.
+type handoff struct {
+	to   AppState
+	text string
+}
+
+// launch opens a mode from the command palette, whatever the screen. msg is what the main menu
+// sends for it, e.g. switchToSearch.
+type launch struct {
+	msg tea.Msg
+}
+
+// navigateBack and navigateForward move through the engine's history instead of to a fixed state.
+type navigateBack struct{}
+type navigateForward struct{}
+
+// transitionMessages are the messages the transition table must handle. navigateBack and
+// navigateForward are not among them, they move through the history instead.
+var transitionMessages = []tea.Msg{
+	switchToSearch{},
+	switchToDictionaryNew{},
+	switchToDetail{},
+	switchToLoading{},
+	switchToError{},
+	switchToTranslate{},
+	switchToTranslateDetail{},
+	switchToMenu{},
+	switchToExplainer{},
+	switchToExplainerDetail{},
+	switchToCompare{},
+	switchToKanji{},
+	switchToKanjiDetail{},
+	handoff{},
+	launch{},
+}
@@ -1,21 +1,195 @@
 package engine
 
 import (
+	"errors"
+	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
+	"io"
 	"reflect"
+	"strings"
 )
 
-type TransitionHandler func(msg tea.Msg) (nextState AppState, cmds []tea.Cmd)
+// Transition declares one edge of the UI state machine: on Msg, from any of From
+// (every state when empty), move to To if Guard allows it. Enter prepares the destination model.
+type Transition struct {
+	From  []AppState
+	Msg   tea.Msg
+	Guard func(msg tea.Msg) bool
+	When  string
+	To    AppState
+	Enter func(msg tea.Msg) []tea.Cmd
+}
+
+func (t Transition) allows(from AppState, msg tea.Msg) bool {
+	if len(t.From) > 0 && !t.leaves(from) {
+		return false
+	}
+
+	return t.Guard == nil || t.Guard(msg)
+}
+
+func (t Transition) leaves(from AppState) bool {
+	if len(t.From) == 0 {
+		return true
+	}
+
+	for _, f := range t.From {
+		if f == from {
+			return true
+		}
+	}
+
+	return false
+}
+
+// overlaps reports whether t and other leave from a common state, and names it.
+func (t Transition) overlaps(other Transition) (string, bool) {
+	switch {
+	case len(t.From) == 0 && len(other.From) == 0:
+		return "any", true
+	case len(t.From) == 0:
+		return other.From[0].String(), true
+	}
+
+	for _, f := range t.From {
+		if other.leaves(f) {
+			return f.String(), true
+		}
+	}
+
+	return "", false
+}
+
+func (t Transition) label() string {
+	name := reflect.TypeOf(t.Msg).Name()
+	if t.When != "" {
+		name += " [" + t.When + "]"
+	}
+
+	return name
+}
 
 type TransitionRouter struct {
-	handlers map[reflect.Type]TransitionHandler
+	transitions map[reflect.Type][]Transition
+	table       []Transition
 }
 
-func (r *TransitionRouter) Register(msgSample tea.Msg, h TransitionHandler) {
-	r.handlers[reflect.TypeOf(msgSample)] = h
+func NewTransitionRouter(table ...Transition) *TransitionRouter {
+	r := &TransitionRouter{
+		transitions: make(map[reflect.Type][]Transition),
+		table:       table,
+	}
+
+	for _, t := range table {
+		typ := reflect.TypeOf(t.Msg)
+		r.transitions[typ] = append(r.transitions[typ], t)
+	}
+
+	return r
 }
 
-func (r *TransitionRouter) Handle(msg tea.Msg) (TransitionHandler, bool) {
-	h, ok := r.handlers[reflect.TypeOf(msg)]
-	return h, ok
+// Handle finds the first declared transition that msg triggers from the given state.
+func (r *TransitionRouter) Handle(from AppState, msg tea.Msg) (Transition, bool) {
+	for _, t := range r.transitions[reflect.TypeOf(msg)] {
+		if t.allows(from, msg) {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
+
+// Validate reports every message without a transition, every transition an earlier unguarded
+// one always takes over from some state, and every state that can't be reached from root.
+func (r *TransitionRouter) Validate(root AppState, states []AppState, messages []tea.Msg) error {
+	var errs []error
+	for _, m := range messages {
+		if _, ok := r.transitions[reflect.TypeOf(m)]; !ok {
+			errs = append(errs, fmt.Errorf("message %s has no transition", reflect.TypeOf(m).Name()))
+		}
+	}
+
+	for i, t := range r.table {
+		for _, earlier := range r.table[:i] {
+			if earlier.Guard != nil || reflect.TypeOf(earlier.Msg) != reflect.TypeOf(t.Msg) {
+				continue
+			}
+
+			if from, ok := earlier.overlaps(t); ok {
+				errs = append(errs, fmt.Errorf("transition %s from %s to %s is shadowed by the one to %s", t.label(), from, t.To, earlier.To))
+			}
+		}
+	}
+
+	reached := map[AppState]bool{root: true}
+	queue := []AppState{root}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		for _, t := range r.table {
+			if t.leaves(from) && !reached[t.To] {
+				reached[t.To] = true
+				queue = append(queue, t.To)
+			}
+		}
+	}
+
+	for _, s := range states {
+		if !reached[s] {
+			errs = append(errs, fmt.Errorf("state %s is unreachable from %s", s, root))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Dot writes the transition table as a Graphviz digraph. Transitions allowed from every
+// state leave from a dashed "any" node instead of every state.
+func (r *TransitionRouter) Dot(w io.Writer, states []AppState) error {
+	var b strings.Builder
+	b.WriteString("digraph ui {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, style=rounded];\n")
+	b.WriteString("\tany [label=\"any state\", style=dashed];\n")
+
+	for _, s := range states {
+		b.WriteString(fmt.Sprintf("\t%q;\n", s.String()))
+	}
+
+	for _, t := range r.table {
+		if len(t.From) == 0 {
+			b.WriteString(fmt.Sprintf("\tany -> %q [label=%q, style=dashed];\n", t.To.String(), t.label()))
+			continue
+		}
+
+		for _, f := range t.From {
+			b.WriteString(fmt.Sprintf("\t%q -> %q [label=%q];\n", f.String(), t.To.String(), t.label()))
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Table writes the transition table as plain text, one transition per line.
+func (r *TransitionRouter) Table(w io.Writer) error {
+	var b strings.Builder
+	for _, t := range r.table {
+		from := "any"
+		if len(t.From) > 0 {
+			names := make([]string, len(t.From))
+			for i, f := range t.From {
+				names[i] = f.String()
+			}
+			from = strings.Join(names, ", ")
+		}
+
+		b.WriteString(fmt.Sprintf("%-40s %-45s -> %s\n", from, t.label(), t.To))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
 }
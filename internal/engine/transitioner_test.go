@@ -0,0 +1,155 @@
+package engine
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTransitionRouterValidate(t *testing.T) {
+	toMenu := Transition{Msg: switchToMenu{}, To: StateMenu}
+	toSearch := Transition{From: []AppState{StateMenu}, Msg: switchToSearch{}, To: StateSearch}
+
+	tests := []struct {
+		name     string
+		table    []Transition
+		states   []AppState
+		messages []tea.Msg
+		want     []string
+	}{
+		{
+			name:     "valid",
+			table:    []Transition{toMenu, toSearch},
+			states:   []AppState{StateMenu, StateSearch},
+			messages: []tea.Msg{switchToMenu{}, switchToSearch{}},
+		},
+		{
+			name:     "message without a transition",
+			table:    []Transition{toMenu, toSearch},
+			states:   []AppState{StateMenu, StateSearch},
+			messages: []tea.Msg{switchToMenu{}, switchToSearch{}, switchToKanji{}},
+			want:     []string{"message switchToKanji has no transition"},
+		},
+		{
+			name: "unreachable states",
+			table: []Transition{
+				toMenu,
+				toSearch,
+				{From: []AppState{StateKanji}, Msg: switchToKanjiDetail{}, To: StateKanjiDetail},
+			},
+			states: []AppState{StateMenu, StateSearch, StateKanji, StateKanjiDetail},
+			want: []string{
+				"state Kanji is unreachable from Menu",
+				"state Kanji entry is unreachable from Menu",
+			},
+		},
+		{
+			name: "reachable through several hops",
+			table: []Transition{
+				toSearch,
+				{From: []AppState{StateSearch}, Msg: switchToLoading{}, To: StateLoading},
+				{From: []AppState{StateLoading}, Msg: switchToDictionaryNew{}, To: StateDictionaryList},
+			},
+			states: []AppState{StateMenu, StateSearch, StateLoading, StateDictionaryList},
+		},
+		{
+			name: "duplicate transition",
+			table: []Transition{
+				toMenu,
+				toSearch,
+				{From: []AppState{StateMenu}, Msg: switchToSearch{}, To: StateKanji},
+			},
+			states: []AppState{StateMenu, StateSearch},
+			want:   []string{"transition switchToSearch from Menu to Kanji is shadowed by the one to Search"},
+		},
+		{
+			name: "ambiguous with a transition from any state",
+			table: []Transition{
+				toMenu,
+				toSearch,
+				{From: []AppState{StateSearch}, Msg: switchToMenu{}, When: "from search", To: StateMenu},
+			},
+			states: []AppState{StateMenu, StateSearch},
+			want:   []string{"transition switchToMenu [from search] from Search to Menu is shadowed by the one to Menu"},
+		},
+		{
+			name: "both from any state",
+			table: []Transition{
+				toMenu,
+				{Msg: switchToMenu{}, To: StateSearch},
+			},
+			states: []AppState{StateMenu, StateSearch},
+			want:   []string{"transition switchToMenu from any to Search is shadowed by the one to Menu"},
+		},
+		{
+			name: "disjoint sources",
+			table: []Transition{
+				toSearch,
+				{From: []AppState{StateSearch}, Msg: switchToSearch{}, To: StateKanji},
+			},
+			states: []AppState{StateMenu, StateSearch, StateKanji},
+		},
+		{
+			name: "guarded transitions",
+			table: []Transition{
+				{From: []AppState{StateMenu}, Msg: handoff{}, Guard: handoffTo(StateExplainer), When: "to Explain", To: StateExplainer},
+				{From: []AppState{StateMenu}, Msg: handoff{}, Guard: handoffTo(StateTranslate), When: "to Translate", To: StateTranslate},
+			},
+			states: []AppState{StateMenu, StateExplainer, StateTranslate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTransitionRouter(tt.table...).Validate(StateMenu, tt.states, tt.messages)
+
+			var got []string
+			if err != nil {
+				got = strings.Split(err.Error(), "\n")
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineTransitionsValidate(t *testing.T) {
+	e := &Engine{models: make(map[AppState]tea.Model)}
+	if err := NewTransitionRouter(e.transitions()...).Validate(StateMenu, appStates, transitionMessages); err != nil {
+		t.Errorf("the engine transition table doesn't validate:\n%v", err)
+	}
+}
+
+func TestTransitionRouterDot(t *testing.T) {
+	r := NewTransitionRouter(
+		Transition{Msg: switchToMenu{}, To: StateMenu},
+		Transition{From: []AppState{StateMenu, StateDetail}, Msg: switchToSearch{}, To: StateSearch},
+		Transition{From: []AppState{StateDetail}, Msg: handoff{}, Guard: handoffTo(StateExplainer), When: "to Explain", To: StateExplainer},
+	)
+
+	var b strings.Builder
+	if err := r.Dot(&b, []AppState{StateMenu, StateSearch, StateDetail, StateExplainer}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph ui {
+	rankdir=LR;
+	node [shape=box, style=rounded];
+	any [label="any state", style=dashed];
+	"Menu";
+	"Search";
+	"Entry";
+	"Explain";
+	any -> "Menu" [label="switchToMenu", style=dashed];
+	"Menu" -> "Search" [label="switchToSearch"];
+	"Entry" -> "Search" [label="switchToSearch"];
+	"Entry" -> "Explain" [label="handoff [to Explain]"];
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("Dot() =\n%s\nwant\n%s", got, want)
+	}
+}
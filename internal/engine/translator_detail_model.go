@@ -1,18 +1,50 @@
 package engine
 
 import (
+	"context"
+	"fmt"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/view"
+	"os"
+	"strings"
 )
 
+type backTranslatedMsg struct {
+	res []domain.RoundTrip
+	err error
+}
+
+type copiedMsg struct {
+	what string
+	err  error
+}
+
 type TranslationDetailModel struct {
-	tr []domain.Translation
+	viewport pager
+
+	input    string
+	sources  []string
+	provider string
+	tr       []domain.TranslationGroup
+	selected int
+	line     int // where the selected paragraph starts in the viewport
+
+	checking bool
+	trips    []domain.RoundTrip
+	err      error
+	status   string
+
+	registry *domain.TranslatorRegistry
 }
 
-func NewTranslationDetailModel() *TranslationDetailModel {
+func NewTranslationDetailModel(registry *domain.TranslatorRegistry) *TranslationDetailModel {
 	return &TranslationDetailModel{
-		tr: make([]domain.Translation, 0),
+		viewport: newPager(100, 20),
+		tr:       make([]domain.TranslationGroup, 0),
+		registry: registry,
 	}
 }
 
@@ -20,20 +52,195 @@ func (ddm *TranslationDetailModel) Init() tea.Cmd {
 	return nil
 }
 
+func (ddm *TranslationDetailModel) backTranslateCmd(ctx context.Context) tea.Cmd {
+	input, provider, groups := ddm.input, ddm.provider, ddm.tr
+	return func() tea.Msg {
+		if len(groups) == 0 || len(groups[0].Translations) == 0 {
+			return backTranslatedMsg{err: fmt.Errorf("nothing to back-translate")}
+		}
+
+		source, err := domain.ParseTargetLang(groups[0].Translations[0].DetectedSourceLanguage)
+		if err != nil {
+			return backTranslatedMsg{err: err}
+		}
+
+		tr, err := ddm.registry.Get(provider)
+		if err != nil {
+			return backTranslatedMsg{err: err}
+		}
+
+		trips, err := domain.BackTranslate(ctx, tr, source, input, groups)
+		return backTranslatedMsg{res: trips, err: err}
+	}
+}
+
+// copyCmd puts the text on the clipboard with an OSC52 sequence, so it also works over SSH.
+// tmux and screen only pass it on to the terminal wrapped in their own passthrough.
+func copyCmd(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+
+		_, err := seq.WriteTo(os.Stdout)
+		return copiedMsg{what: what, err: err}
+	}
+}
+
+// segments flattens the translated paragraphs of every group, in render order.
+func (ddm *TranslationDetailModel) segments() []domain.Translation {
+	segs := make([]domain.Translation, 0)
+	for _, g := range ddm.tr {
+		segs = append(segs, g.Translations...)
+	}
+
+	return segs
+}
+
+func (ddm *TranslationDetailModel) result() string {
+	var b strings.Builder
+	for i, g := range ddm.tr {
+		if len(ddm.tr) > 1 {
+			if i > 0 {
+				b.WriteString("\n\n")
+			}
+			b.WriteString(g.Lang.String() + ":\n")
+		}
+
+		texts := make([]string, len(g.Translations))
+		for j, t := range g.Translations {
+			texts[j] = t.Text
+		}
+		b.WriteString(strings.Join(texts, "\n\n"))
+	}
+
+	return b.String()
+}
+
+// japanese picks the Japanese text to hand over to the explainer: the selected paragraph when it is
+// Japanese, otherwise the whole Japanese output, or the source when it was Japanese to begin with.
+func (ddm *TranslationDetailModel) japanese() string {
+	var seg int
+	var all []string
+	for _, g := range ddm.tr {
+		for _, t := range g.Translations {
+			if g.Lang == domain.TargetJapanese {
+				if seg == ddm.selected {
+					return t.Text
+				}
+				all = append(all, t.Text)
+			}
+			seg++
+		}
+	}
+
+	if len(all) > 0 {
+		return strings.Join(all, "\n")
+	}
+
+	segs := ddm.segments()
+	if ddm.selected < len(segs) && len(ddm.sources) > 0 {
+		if lang, err := domain.ParseTargetLang(segs[ddm.selected].DetectedSourceLanguage); err == nil && lang == domain.TargetJapanese {
+			return ddm.sources[ddm.selected%len(ddm.sources)]
+		}
+	}
+
+	return ""
+}
+
 func (ddm *TranslationDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		ddm.viewport.Width = msg.Width
+		ddm.viewport.Height = max(msg.Height-view.FooterHeight-2, view.MinViewportHeight) // pager and status lines
+		ddm.render()
+		return ddm, nil
+
+	case backTranslatedMsg:
+		ddm.checking = false
+		ddm.trips, ddm.err = msg.res, msg.err
+		ddm.render()
+		return ddm, nil
+
+	case copiedMsg:
+		ddm.status = "Copied " + msg.what
+		if msg.err != nil {
+			ddm.status = "Copy failed: " + msg.err.Error()
+		}
+		return ddm, nil
+
+	case tea.MouseMsg:
+		return ddm, ddm.viewport.Update(msg)
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlQ:
+		if ddm.viewport.Searching() {
+			return ddm, ddm.viewport.Update(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.BackTranslate):
+			if ddm.checking || ddm.trips != nil {
+				return ddm, nil
+			}
+
+			ddm.checking = true
+			ddm.err = nil
+			ddm.render()
+			return ddm, ddm.backTranslateCmd(context.Background())
+
+		case key.Matches(msg, keys.Explain):
+			text := ddm.japanese()
+			if text == "" {
+				ddm.status = "No Japanese text to explain"
+				return ddm, nil
+			}
+
 			return ddm, func() tea.Msg {
-				return switchToTranslate{}
+				return handoff{to: StateExplainer, text: text}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Next):
+			if n := len(ddm.segments()); n > 0 {
+				ddm.selected = (ddm.selected + 1) % n
+				ddm.render()
+				ddm.scrollToSelected()
+			}
+			return ddm, nil
+
+		case key.Matches(msg, keys.Prev):
+			if n := len(ddm.segments()); n > 0 {
+				ddm.selected = (ddm.selected - 1 + n) % n
+				ddm.render()
+				ddm.scrollToSelected()
+			}
+			return ddm, nil
+
+		case key.Matches(msg, keys.Back):
+			return ddm, func() tea.Msg {
+				return navigateBack{}
+			}
+
+		case key.Matches(msg, keys.Quit):
 			return ddm, tea.Quit
+
+		case key.Matches(msg, keys.CopyItem):
+			segs := ddm.segments()
+			if ddm.selected < len(segs) {
+				return ddm, copyCmd("paragraph", segs[ddm.selected].Text)
+			}
+			return ddm, nil
+
+		case key.Matches(msg, keys.CopyAll):
+			return ddm, copyCmd("result", ddm.result())
 		}
+
+		cmd = ddm.viewport.Update(msg)
+		return ddm, cmd
 	}
 
 	return ddm, cmd
@@ -41,17 +248,83 @@ func (ddm *TranslationDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (ddm *TranslationDetailModel) View() string {
 	fnt := view.FootNoteStyle.Padding(1, 0, 2, 4).Render(
-		"ctrl+q: back to translation\n",
+		footer(StateTranslateDetail),
 	)
 
 	if ddm.tr == nil || len(ddm.tr) == 0 {
 		return "" + fnt
 	}
 
-	return view.BaseViewStyle.Render(view.RenderTranslation(ddm.tr)) + fnt
+	var status string
+	if ddm.status != "" {
+		status = "\n" + view.MutedStyle.PaddingLeft(view.PaddingLeftTwo).Render(ddm.status)
+	}
+
+	return ddm.viewport.View() + status + fnt
+}
+
+func (ddm *TranslationDetailModel) render() {
+	width := ddm.viewport.Width - ddm.viewport.Style.GetHorizontalFrameSize() - 2
+	var body string
+	body, ddm.line = view.RenderAlignedTranslation(ddm.sources, ddm.tr, ddm.selected, width)
+
+	switch {
+	case ddm.checking:
+		body += view.MutedStyle.Render("Back-translating...")
+	case ddm.err != nil:
+		body += view.MutedStyle.Render("Back-translation error: " + ddm.err.Error())
+	case ddm.trips != nil:
+		body += view.RenderRoundTrips(ddm.trips, width)
+	}
+
+	ddm.viewport.SetContent(body)
+}
+
+// scrollToSelected brings the selected paragraph into view when it is out of it, like pager.jump.
+func (ddm *TranslationDetailModel) scrollToSelected() {
+	p := &ddm.viewport
+	if ddm.line < p.YOffset || ddm.line >= p.YOffset+p.Height-p.Style.GetVerticalFrameSize() {
+		p.SetYOffset(ddm.line - p.Height/2)
+	}
 }
 
-func (ddm *TranslationDetailModel) SetItem(translations []domain.Translation) tea.Cmd {
-	ddm.tr = translations
+func (ddm *TranslationDetailModel) SetItem(input, provider string, translations []domain.TranslationGroup) tea.Cmd {
+	ddm.input, ddm.provider, ddm.tr = input, provider, translations
+	ddm.sources = domain.SplitParagraphs(input)
+	ddm.selected = 0
+	ddm.checking, ddm.trips, ddm.err, ddm.status = false, nil, nil, ""
+
+	ddm.render()
+	ddm.viewport.GotoTop()
 	return nil
 }
+
+func (ddm *TranslationDetailModel) Commands() []command {
+	if text := ddm.japanese(); text != "" {
+		return []command{{title: "Explain the Japanese translation", run: dispatch(handoff{to: StateExplainer, text: text})}}
+	}
+
+	return nil
+}
+
+func (ddm *TranslationDetailModel) Capturing() bool {
+	return ddm.viewport.Searching()
+}
+
+func (ddm *TranslationDetailModel) applyTheme() {
+	ddm.viewport.applyTheme()
+}
+
+func (ddm *TranslationDetailModel) Snapshot() tea.Model {
+	c := *ddm
+	return &c
+}
+
+func (ddm *TranslationDetailModel) Breadcrumb() string {
+	langs := make([]string, len(ddm.tr))
+	for i, g := range ddm.tr {
+		langs[i] = g.Lang.Code()
+	}
+
+	return "→ " + strings.Join(langs, "/")
+}
@@ -3,22 +3,47 @@ package engine
 import (
 	"context"
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"github.com/ziliscite/dictionary-cli/internal/view"
-	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
+// suggestDelay is how long typing has to pause before the input is looked up in the translation memory.
+const suggestDelay = 250 * time.Millisecond
+
+// suggestTick fires once typing paused, suggested carries the memory matches found then.
+// Both are dropped when the input changed again since.
+type suggestTick struct {
+	seq int
+}
+
+type suggested struct {
+	seq     int
+	matches []domain.MemoryMatch
+}
+
 type TranslatorModel struct {
 	ta   textarea.Model
 	pter int
 	des  []domain.TargetLang
+	sel  map[domain.TargetLang]bool
+
+	prov      int
+	providers []string
+	registry  *domain.TranslatorRegistry
 
-	sc domain.Translator
+	memory      *domain.TranslationMemory
+	suggestions []domain.MemoryMatch
+	suggestSeq  int // the latest lookup asked for
 }
 
-func NewTranslatorModel(httpClient *http.Client, k string) *TranslatorModel {
+func NewTranslatorModel(registry *domain.TranslatorRegistry, memory *domain.TranslationMemory) *TranslatorModel {
 	ta := textarea.New()
 	ta.CharLimit = 2000
 	//ta.Placeholder = "私はバカな男だ"
@@ -28,8 +53,13 @@ func NewTranslatorModel(httpClient *http.Client, k string) *TranslatorModel {
 		ta:   ta,
 		pter: 0,
 		des:  []domain.TargetLang{domain.TargetJapanese, domain.TargetEnglish, domain.TargetIndonesia},
+		sel:  make(map[domain.TargetLang]bool),
+
+		prov:      0,
+		providers: registry.Names(),
+		registry:  registry,
 
-		sc: domain.NewDeepLClient(k, httpClient),
+		memory: memory,
 	}
 }
 
@@ -37,36 +67,118 @@ func (im *TranslatorModel) Init() tea.Cmd {
 	return textarea.Blink
 }
 
+func (im *TranslatorModel) provider() string {
+	if len(im.providers) == 0 {
+		return ""
+	}
+
+	return im.providers[im.prov%len(im.providers)]
+}
+
+// targets returns the selected languages in menu order, or the one under the cursor when none is selected.
+func (im *TranslatorModel) targets() []domain.TargetLang {
+	langs := make([]domain.TargetLang, 0, len(im.des))
+	for _, d := range im.des {
+		if im.sel[d] {
+			langs = append(langs, d)
+		}
+	}
+
+	if len(langs) == 0 {
+		langs = append(langs, im.des[im.pter%len(im.des)])
+	}
+
+	return langs
+}
+
 func (im *TranslatorModel) View() string {
 	pter := im.pter
 	deslen := len(im.des)
-	targetLanguage := im.des[pter%deslen].String()
-	prev := im.des[(deslen+pter-1)%deslen].String()
-	next := im.des[(pter+1)%deslen].String()
+
+	targets := im.targets()
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.String()
+	}
+
+	langs := make([]string, len(im.des))
+	for i, d := range im.des {
+		label := d.String()
+		if i == pter%deslen {
+			label = view.DotStyle.Render(label)
+		}
+
+		langs[i] = checkbox(label, im.sel[d])
+	}
 
 	return view.LesterViewStyle.Render(fmt.Sprintf(
-		"What do you want to translate to %s?\n\n%s",
-		targetLanguage, im.ta.View(),
+		"What do you want to translate to %s? %s\n%s\n\n%s%s",
+		strings.Join(names, ", "), view.MutedStyle.Render("via "+im.provider()), strings.Join(langs, "  "), im.ta.View(),
+		view.RenderMemoryMatches(im.suggestions),
 	)) + view.LesterViewNoteStyle.Render(
-		fmt.Sprintf("esc/ctrl+c: exit • ctrl+q: back to menu • shift+left: %s • shift+right: %s • ctrl+t: translate\n", prev, next),
+		footer(StateTranslate),
 	)
 }
 
-func (im *TranslatorModel) translateCmd(ctx context.Context, lang domain.TargetLang, query string) tea.Cmd {
+func (im *TranslatorModel) translateCmd(ctx context.Context, provider string, langs []domain.TargetLang, query string) tea.Cmd {
 	return tea.Batch(
 		func() tea.Msg {
 			return switchToLoading{}
 		},
-		func() tea.Msg {
-			res, err := im.sc.Translate(ctx, lang, query)
-			if err != nil {
-				return switchToError{
-					err: err,
-				}
+		tea.Sequence(im.runTranslation(ctx, provider, langs, query), im.saveMemoryCmd),
+	)
+}
+
+// saveMemoryCmd saves the pairs the last translation recorded, once every language is done.
+// A failure doesn't lose the translations, it is only reported.
+func (im *TranslatorModel) saveMemoryCmd() tea.Msg {
+	if im.memory == nil {
+		return nil
+	}
+
+	if err := im.memory.Save(); err != nil {
+		return notify{"Translation memory not saved: " + err.Error()}
+	}
+
+	return nil
+}
+
+func (im *TranslatorModel) runTranslation(ctx context.Context, provider string, langs []domain.TargetLang, query string) tea.Cmd {
+	return func() tea.Msg {
+		tr, err := im.registry.Get(provider)
+		if err != nil {
+			return switchToError{err}
+		}
+
+		if im.memory != nil {
+			tr = domain.NewMemoryTranslator(tr, im.memory)
+		}
+
+		res, err := domain.TranslateMany(ctx, tr, langs, domain.SplitParagraphs(query)...)
+		if err != nil {
+			return switchToError{
+				err: err,
 			}
+		}
+
+		return switchToTranslateDetail{
+			input:    query,
+			provider: provider,
+			res:      res,
+		}
+	}
+}
 
-			return switchToTranslateDetail{
-				res: res,
+func (im *TranslatorModel) compareCmd(ctx context.Context, lang domain.TargetLang, query string) tea.Cmd {
+	return tea.Batch(
+		func() tea.Msg {
+			return switchToLoading{}
+		},
+		func() tea.Msg {
+			return switchToCompare{
+				input: query,
+				lang:  lang,
+				res:   domain.CompareTranslations(ctx, im.registry, lang, query),
 			}
 		},
 	)
@@ -77,21 +189,71 @@ func (im *TranslatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		im.ta.SetWidth(max(msg.Width-view.PaddingLeftTwo*2, view.DefaultTextWidth))
+		im.ta.SetHeight(max(msg.Height/3, view.MinViewportHeight))
+		return im, nil
+
+	case suggestTick:
+		if msg.seq != im.suggestSeq {
+			return im, nil
+		}
+
+		memory, source, lang := im.memory, im.ta.Value(), im.targets()[0]
+		return im, func() tea.Msg {
+			return suggested{seq: msg.seq, matches: memory.Lookup(source, lang, 0.7, 3)}
+		}
+
+	case suggested:
+		if msg.seq == im.suggestSeq {
+			im.suggestions = msg.matches
+		}
+		return im, nil
+
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyShiftLeft:
+		switch {
+		case key.Matches(msg, keys.PrevLang):
 			im.pter--
 			if im.pter < 0 {
 				im.pter = len(im.des) - 1
 			}
+			cmds = append(cmds, im.suggest())
 
-		case tea.KeyShiftRight:
+		case key.Matches(msg, keys.NextLang):
 			im.pter++
 			if im.pter >= len(im.des) {
 				im.pter = 0
 			}
+			cmds = append(cmds, im.suggest())
+
+		case key.Matches(msg, keys.PrevProvider):
+			im.prov--
+			if im.prov < 0 {
+				im.prov = len(im.providers) - 1
+			}
+
+		case key.Matches(msg, keys.NextProvider):
+			im.prov++
+			if im.prov >= len(im.providers) {
+				im.prov = 0
+			}
+
+		case key.Matches(msg, keys.ToggleLang):
+			lang := im.des[im.pter%len(im.des)]
+			im.sel[lang] = !im.sel[lang]
+			return im, im.suggest()
+
+		case key.Matches(msg, keys.Translate):
+			query := im.ta.Value()
+			if query == "" {
+				return im, nil
+			}
+
+			im.ta.Reset()
+			im.clearSuggestions()
+			return im, im.translateCmd(context.Background(), im.provider(), im.targets(), query)
 
-		case tea.KeyCtrlT:
+		case key.Matches(msg, keys.Compare):
 			query := im.ta.Value()
 			if query == "" {
 				return im, nil
@@ -100,15 +262,17 @@ func (im *TranslatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			lang := im.des[im.pter%len(im.des)]
 
 			im.ta.Reset()
-			return im, im.translateCmd(context.Background(), lang, query)
+			im.clearSuggestions()
+			return im, im.compareCmd(context.Background(), lang, query)
 
-		case tea.KeyCtrlQ:
+		case key.Matches(msg, keys.Back):
 			im.ta.Reset()
+			im.clearSuggestions()
 			return im, func() tea.Msg {
-				return switchToMenu{}
+				return navigateBack{}
 			}
 
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case key.Matches(msg, keys.Quit):
 			return im, tea.Quit
 
 		default:
@@ -119,11 +283,89 @@ func (im *TranslatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	value := im.ta.Value()
 	im.ta, cmd = im.ta.Update(msg)
 	cmds = append(cmds, cmd)
+
+	if value != im.ta.Value() {
+		cmds = append(cmds, im.suggest())
+	}
+
 	return im, tea.Batch(cmds...)
 }
 
+// suggest looks the input up in the translation memory before anything is sent to a provider,
+// once typing pauses and off the UI goroutine.
+func (im *TranslatorModel) suggest() tea.Cmd {
+	if im.memory == nil {
+		return nil
+	}
+
+	im.suggestSeq++
+	seq := im.suggestSeq
+	return tea.Tick(suggestDelay, func(time.Time) tea.Msg {
+		return suggestTick{seq}
+	})
+}
+
+// clearSuggestions drops the suggestions, and the lookup still on its way.
+func (im *TranslatorModel) clearSuggestions() {
+	im.suggestions = nil
+	im.suggestSeq++
+}
+
 func (im *TranslatorModel) Focus() tea.Cmd {
 	return im.ta.Focus()
 }
+
+// SetTarget points the language cursor at lang, dropping the multi-language selection.
+func (im *TranslatorModel) SetTarget(lang domain.TargetLang) tea.Cmd {
+	for i, d := range im.des {
+		if d == lang {
+			im.pter = i
+		}
+	}
+
+	im.sel = make(map[domain.TargetLang]bool)
+	return tea.Batch(im.suggest(), im.ta.Focus())
+}
+
+func (im *TranslatorModel) Launchers() []command {
+	commands := []command{{title: "Translate…", run: dispatch(launch{switchToTranslate{}})}}
+	for _, d := range im.des {
+		commands = append(commands, command{
+			title: "Translate to " + d.String() + "…",
+			run:   dispatch(launch{switchToTranslate{lang: &d}}),
+		})
+	}
+
+	if im.memory != nil {
+		commands = append(commands, command{title: "Export translation memory", run: im.exportCmd})
+	}
+
+	return commands
+}
+
+// exportCmd writes the translation memory as TMX next to where it is saved.
+func (im *TranslatorModel) exportCmd() tea.Msg {
+	path := strings.TrimSuffix(im.memory.Path(), filepath.Ext(im.memory.Path())) + ".tmx"
+
+	f, err := os.Create(path)
+	if err != nil {
+		return notify{"Export failed: " + err.Error()}
+	}
+	defer f.Close()
+
+	if err = im.memory.ExportTMX(f); err != nil {
+		return notify{"Export failed: " + err.Error()}
+	}
+
+	return notify{fmt.Sprintf("Exported %d pairs to %s", im.memory.Len(), path)}
+}
+
+// SetInput prefills the text to translate, e.g. with a sense handed over from the dictionary.
+func (im *TranslatorModel) SetInput(text string) tea.Cmd {
+	im.ta.Reset()
+	im.ta.SetValue(text)
+	return tea.Batch(im.suggest(), im.ta.Focus())
+}
@@ -0,0 +1,45 @@
+package view
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"strings"
+)
+
+// RenderComparison lays the providers out in columns, sharing the given width.
+func RenderComparison(comparisons []domain.Comparison, width int) string {
+	if len(comparisons) == 0 {
+		return ""
+	}
+
+	colWidth := max(width/len(comparisons)-2, 20)
+	column := BorderStyle.Padding(0, 1).Width(colWidth)
+
+	cols := make([]string, 0, len(comparisons))
+	for _, c := range comparisons {
+		var b strings.Builder
+		b.WriteString(WordStyleBold.Underline(true).Render(c.Provider) + "\n")
+		b.WriteString(MutedStyle.Render(fmt.Sprintf("%dms • %d chars", c.Latency.Milliseconds(), c.Characters)) + "\n\n")
+
+		if c.Err != nil {
+			b.WriteString(HighlightStyle.UnsetPaddingLeft().Render("Error: " + c.Err.Error()))
+		} else {
+			for _, t := range c.Translations {
+				b.WriteString(WordStyle.Render(t.Text) + "\n")
+			}
+		}
+
+		cols = append(cols, column.Render(b.String()))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, cols...)
+}
+
+func RenderVerdict(v *domain.Verdict) string {
+	if v == nil {
+		return ""
+	}
+
+	return DotStyle.Render("> ") + WordStyleBold.Render("Most natural: "+v.Best) + "\n" + indentLines(WordStyle.Render(v.Reason), "  ")
+}
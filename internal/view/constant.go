@@ -10,27 +10,67 @@ const (
 	PaddingLeftOne   = 2
 	PaddingLeftTwo   = 4
 
-	ColorMain      = "252"
-	ColorMuted     = "240"
-	ColorHighlight = "170"
-	ColorSpinner   = "69"
-	ColorDot       = "99"
-	ColorBorder    = "62"
+	// FooterHeight is what a detail view keeps below its viewport for the key help and breadcrumbs.
+	FooterHeight      = 6
+	MinViewportHeight = 5
+	PaletteWidth      = 48
 )
 
+// The styles are derived from the active theme, see ApplyTheme.
 var (
-	HighlightStyle = lipgloss.NewStyle().PaddingLeft(PaddingLeftOne).Foreground(lipgloss.Color(ColorHighlight))
-	NormalStyle    = lipgloss.NewStyle().PaddingLeft(PaddingLeftTwo)
-	WordStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMain))
-	WordStyleBold  = WordStyle.Bold(true)
-	MutedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorMuted))
+	HighlightStyle lipgloss.Style
+	NormalStyle    lipgloss.Style
+	WordStyle      lipgloss.Style
+	WordStyleBold  lipgloss.Style
+	MutedStyle     lipgloss.Style
+	MutedStyleBold lipgloss.Style
+
+	DotStyle     lipgloss.Style
+	BorderStyle  lipgloss.Style
+	SpinnerStyle lipgloss.Style
+
+	LesterViewStyle     lipgloss.Style
+	LesterViewNoteStyle lipgloss.Style
+	BaseViewStyle       lipgloss.Style
+	FootNoteStyle       lipgloss.Style
+
+	MatchStyle        lipgloss.Style
+	CurrentMatchStyle lipgloss.Style
+
+	BadgeStyle  lipgloss.Style
+	CommonStyle lipgloss.Style
+
+	TabStyle       lipgloss.Style
+	ActiveTabStyle lipgloss.Style
+
+	CursorCellStyle lipgloss.Style
+)
+
+func buildStyles(t Theme) {
+	HighlightStyle = lipgloss.NewStyle().PaddingLeft(PaddingLeftOne).Foreground(lipgloss.Color(t.Highlight))
+	NormalStyle = lipgloss.NewStyle().PaddingLeft(PaddingLeftTwo)
+	WordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Main))
+	WordStyleBold = WordStyle.Bold(true)
+	MutedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Muted))
 	MutedStyleBold = MutedStyle.Bold(true)
 
-	DotStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color(ColorDot))
-	BorderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(ColorBorder))
+	DotStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Dot))
+	BorderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(t.Border))
+	SpinnerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Spinner))
 
-	LesterViewStyle     = lipgloss.NewStyle().Padding(1, 2, 1, 0)
+	LesterViewStyle = lipgloss.NewStyle().Padding(1, 2, 1, 0)
 	LesterViewNoteStyle = MutedStyle.Padding(1, 0, 3, 0)
-	BaseViewStyle       = lipgloss.NewStyle().Padding(1, 2, 1, 4)
-	FootNoteStyle       = MutedStyle.Padding(1, 0, 3, 4)
-)
+	BaseViewStyle = lipgloss.NewStyle().Padding(1, 2, 1, 4)
+	FootNoteStyle = MutedStyle.Padding(1, 0, 3, 4)
+
+	MatchStyle = lipgloss.NewStyle().Reverse(true)
+	CurrentMatchStyle = lipgloss.NewStyle().Reverse(true).Foreground(lipgloss.Color(t.Highlight))
+
+	BadgeStyle = lipgloss.NewStyle().Reverse(true).Foreground(lipgloss.Color(t.Dot)).Padding(0, 1)
+	CommonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Spinner))
+
+	TabStyle = MutedStyle.Padding(0, 1)
+	ActiveTabStyle = lipgloss.NewStyle().Padding(0, 1).Bold(true).Underline(true).Foreground(lipgloss.Color(t.Highlight))
+
+	CursorCellStyle = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color(t.Highlight))
+}
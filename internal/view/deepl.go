@@ -1,21 +1,104 @@
 package view
 
 import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"strings"
 )
 
-func RenderTranslation(translations []domain.Translation) string {
-	if len(translations) == 0 {
+// RenderAlignedTranslation puts every source paragraph next to its translation, tagged with the
+// language detected for that segment. Segments are numbered across groups, selected is highlighted
+// and line is where it starts.
+func RenderAlignedTranslation(sources []string, groups []domain.TranslationGroup, selected, width int) (s string, line int) {
+	colWidth := max((width-4)/2, 20)
+	column := lipgloss.NewStyle().Width(colWidth).PaddingRight(2)
+
+	var b strings.Builder
+	var seg int
+	for gi, g := range groups {
+		if gi > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(DotStyle.Render("> ") + WordStyleBold.Render(g.Lang.String()) + "\n\n")
+
+		for i, t := range g.Translations {
+			var src string
+			if i < len(sources) {
+				src = sources[i]
+			}
+
+			marker := "  "
+			text := WordStyle.Render(t.Text)
+			if seg == selected {
+				marker = DotStyle.Render("> ")
+				text = WordStyleBold.Render(t.Text)
+				line = strings.Count(b.String(), "\n")
+			}
+
+			tag := MutedStyleBold.Render("[" + t.DetectedSourceLanguage + "] ")
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+				marker,
+				column.Render(tag+MutedStyle.Render(src)),
+				column.Render(text),
+			) + "\n\n")
+
+			seg++
+		}
+	}
+
+	return b.String(), line
+}
+
+// RenderRoundTrips shows the original next to its back-translation, sentence by sentence.
+func RenderRoundTrips(trips []domain.RoundTrip, width int) string {
+	colWidth := max((width-6)/2, 20)
+	column := lipgloss.NewStyle().Width(colWidth).PaddingRight(2)
+
+	var b strings.Builder
+	for _, rt := range trips {
+		b.WriteString(DotStyle.Render("> ") + WordStyleBold.Render("Round trip via "+rt.Via.String()))
+		b.WriteString(MutedStyle.Render(fmt.Sprintf(" • similarity %.0f%%", rt.Score*100)) + "\n\n")
+
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			column.Render(MutedStyleBold.Render("Original")),
+			column.Render(MutedStyleBold.Render("Round trip")),
+		) + "\n")
+
+		for _, s := range rt.Sentences {
+			score := MutedStyle.Render(fmt.Sprintf("%.0f%%", s.Score*100))
+			if s.Drifted {
+				score = HighlightStyle.UnsetPaddingLeft().Render(fmt.Sprintf("%.0f%% drifted", s.Score*100))
+			}
+
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+				column.Render(WordStyle.Render(s.Original)),
+				column.Render(WordStyle.Render(s.RoundTrip)),
+				score,
+			) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func RenderMemoryMatches(matches []domain.MemoryMatch) string {
+	if len(matches) == 0 {
 		return ""
 	}
 
 	var b strings.Builder
-	b.WriteString("Source: " + WordStyleBold.Underline(true).Render(translations[0].DetectedSourceLanguage) + "\n")
+	b.WriteString("\n\n" + MutedStyleBold.Render("Translation memory:") + "\n")
+	for _, m := range matches {
+		label := fmt.Sprintf("%d%%", m.Percent())
+		if m.Exact() {
+			label = "exact"
+		}
 
-	for _, t := range translations {
-		b.WriteString(t.Text + "\n")
+		b.WriteString(DotStyle.Render("> ") + MutedStyle.Render("["+label+"] "+m.Entry.Source+" → ") + WordStyle.Render(m.Entry.Target) + "\n")
 	}
 
-	return WordStyle.Render(b.String())
+	return b.String()
 }
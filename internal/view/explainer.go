@@ -2,6 +2,8 @@ package view
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
 	"strings"
 )
@@ -109,6 +111,15 @@ func (a Analysis) Render() string {
 	}
 
 	b.WriteString("\n")
+	b.WriteString(a.RenderGrammar())
+
+	return b.String()
+}
+
+// RenderGrammar renders only the grammar points, for when the word-by-word gloss is shown as a table.
+func (a Analysis) RenderGrammar() string {
+	var b strings.Builder
+
 	b.WriteString(WordStyleBold.Render("Grammar Points:") + "\n\n")
 
 	for i, p := range a.GrammarPoints {
@@ -193,3 +204,32 @@ func (u Usage) Render() string {
 	b.WriteString(u.Practice.Render())
 	return b.String()
 }
+
+// GlossTable turns the word-by-word analysis into table rows, one per token.
+func (a Analysis) GlossTable() ([]table.Column, []table.Row) {
+	cols := []table.Column{
+		{Title: "Token", Width: 12},
+		{Title: "Reading", Width: 14},
+		{Title: "Part of speech", Width: 16},
+		{Title: "Meaning", Width: 36},
+	}
+
+	rows := make([]table.Row, len(a.WordByWord))
+	for i, v := range a.WordByWord {
+		rows[i] = table.Row{v.Token, v.Reading, v.Pos, v.Meaning}
+	}
+
+	return cols, rows
+}
+
+func GlossTableStyles(focused bool) table.Styles {
+	s := table.DefaultStyles()
+	s.Header = s.Header.Foreground(lipgloss.Color(active.Main)).BorderStyle(lipgloss.NormalBorder()).BorderBottom(true).BorderForeground(lipgloss.Color(active.Border))
+	s.Cell = s.Cell.Foreground(lipgloss.Color(active.Main))
+	s.Selected = s.Selected.Foreground(lipgloss.Color(active.Main))
+	if focused {
+		s.Selected = s.Selected.Foreground(lipgloss.Color(active.Highlight))
+	}
+
+	return s
+}
@@ -0,0 +1,33 @@
+package view
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HelpStyles colors the key help with the active theme.
+func HelpStyles() help.Styles {
+	key := WordStyle
+	desc := MutedStyle
+	sep := lipgloss.NewStyle().Foreground(lipgloss.Color(active.Border))
+
+	return help.Styles{
+		Ellipsis:       sep,
+		ShortKey:       key,
+		ShortDesc:      desc,
+		ShortSeparator: sep,
+		FullKey:        key,
+		FullDesc:       desc,
+		FullSeparator:  sep,
+	}
+}
+
+// RenderOverlay frames a titled box in the middle of a width x height screen.
+func RenderOverlay(title, body string, width, height int) string {
+	box := BorderStyle.Padding(1, 2).Render(HighlightStyle.PaddingLeft(0).Render(title) + "\n\n" + body)
+	if width == 0 || height == 0 {
+		return box
+	}
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
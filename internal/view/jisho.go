@@ -1,51 +1,336 @@
 package view
 
 import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"slices"
 	"strconv"
 	"strings"
 )
 
-func RenderEntry(entry *domain.Information) string {
+// RenderEntryHeader summarizes the entry on one line: the headword, its tags, the pitch accent
+// of its first form and how many forms and senses it has.
+func RenderEntryHeader(entry *domain.Information, pitch *domain.PitchAccents) string {
+	head := renderHeadword(entry)
+	if len(entry.Japanese) > 0 {
+		if accents := pitch.Lookup(entry.Japanese[0].Word, entry.Japanese[0].Reading); len(accents) > 0 {
+			head += MutedStyle.Render(" · ") + RenderPitch(accents[0])
+		}
+	}
+
+	return head + MutedStyle.Render(fmt.Sprintf(" · %d forms · %d senses", len(entry.Japanese), len(entry.Senses)))
+}
+
+// renderHeadword is the first form of the entry with its reading, common marker and JLPT badges.
+func renderHeadword(entry *domain.Information) string {
+	word, reading := entry.Slug, ""
+	if len(entry.Japanese) > 0 {
+		word, reading = entry.Japanese[0].Word, entry.Japanese[0].Reading
+		if word == "" {
+			word, reading = reading, ""
+		}
+	}
+
+	head := WordStyleBold.Render(word)
+	if reading != "" {
+		head += " " + MutedStyle.Render("【"+reading+"】")
+	}
+	if entry.IsCommon {
+		head += " " + CommonStyle.Render("● common")
+	}
+	for _, level := range entry.JLPT {
+		head += " " + BadgeStyle.Render(strings.ToUpper(strings.TrimPrefix(level, "jlpt-")))
+	}
+
+	return head
+}
+
+// RenderTabs is the tab bar, the active tab stands out.
+func RenderTabs(names []string, active int) string {
+	tabs := make([]string, len(names))
+	for i, name := range names {
+		style := TabStyle
+		if i == active {
+			style = ActiveTabStyle
+		}
+
+		tabs[i] = style.Render(name)
+	}
+
+	return strings.Join(tabs, " ")
+}
+
+// TabAt is the tab drawn at column x of RenderTabs, -1 between or after the tabs.
+func TabAt(names []string, x int) int {
+	for i, name := range names {
+		w := lipgloss.Width(ActiveTabStyle.Render(name))
+		if x < w {
+			return i
+		}
+
+		x -= w + 1 // the space between tabs
+		if x < 0 {
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// RenderSenses lists the senses with their parts of speech, tags and notes, as markdown.
+// The selected one is marked.
+func RenderSenses(entry *domain.Information, selected int) string {
+	if len(entry.Senses) == 0 {
+		return "_No senses._"
+	}
+
 	var b strings.Builder
-	b.WriteString("Entry: **" + entry.Slug + "**")
-	if len(entry.JLPT) > 0 {
-		b.WriteString(" | " + strings.Join(entry.JLPT, ", "))
-	}
-	b.WriteString("\n\n")
-
-	maxLen := max(len(entry.Japanese), len(entry.Senses))
-	for i := 0; i < maxLen; i++ {
-		if i < len(entry.Japanese) {
-			b.WriteString(strconv.Itoa(i+1) + ". ")
-			term := entry.Japanese[i]
-			if term.Word != "" {
-				b.WriteString("**" + term.Word + "** ")
-				b.WriteString("_(" + term.Reading + ")_\n")
-			} else {
-				b.WriteString("*" + term.Reading + "*\n")
+	for i, sense := range entry.Senses {
+		marker := ""
+		if i == selected {
+			marker = "▶ "
+		}
+
+		b.WriteString(fmt.Sprintf("%s**%d.**", marker, i+1))
+		if len(sense.PartsOfSpeech) > 0 {
+			b.WriteString(" _" + strings.Join(sense.PartsOfSpeech, ", ") + "_")
+		}
+		b.WriteString("\n\n")
+
+		for _, def := range sense.EnglishDefinitions {
+			b.WriteString("- " + def + "\n")
+		}
+
+		if notes := append(append([]string{}, sense.Tags...), sense.Info...); len(notes) > 0 {
+			b.WriteString("\n" + strings.Join(notes, "; ") + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// RenderForms lists every written form with its reading and pitch accents, as markdown.
+func RenderForms(entry *domain.Information, pitch *domain.PitchAccents) string {
+	if len(entry.Japanese) == 0 {
+		return "_No forms._"
+	}
+
+	var b strings.Builder
+	for i, term := range entry.Japanese {
+		b.WriteString(strconv.Itoa(i+1) + ". ")
+		if term.Word != "" {
+			b.WriteString("**" + term.Word + "** _(" + term.Reading + ")_")
+		} else {
+			b.WriteString("**" + term.Reading + "** _(kana only)_")
+		}
+
+		if accents := pitch.Lookup(term.Word, term.Reading); len(accents) > 0 {
+			b.WriteString(" — " + pitchMarkdown(accents))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// RenderKanji explains every kanji of the written forms with its KANJIDIC2 data, as markdown.
+// Kanji the dictionary doesn't know only list the forms using them.
+func RenderKanji(entry *domain.Information, kd *domain.Kanjidic) string {
+	var b strings.Builder
+	for _, k := range domain.EntryKanji(entry) {
+		var forms []string
+		for _, term := range entry.Japanese {
+			if strings.ContainsRune(term.Word, k) && !slices.Contains(forms, term.Word) {
+				forms = append(forms, term.Word)
 			}
-			b.WriteString("\n")
 		}
 
-		if i < len(entry.Senses) {
-			sense := entry.Senses[i]
-			b.WriteString(renderSensePlain(sense.EnglishDefinitions, sense.PartsOfSpeech))
+		b.WriteString("## " + string(k) + "\n\n")
+		if info, ok := kd.Lookup(k); ok {
+			b.WriteString(renderKanjiInfo(info))
+		}
+		b.WriteString("- In: " + strings.Join(forms, ", ") + "\n\n")
+	}
+
+	if b.Len() == 0 {
+		return "_This word is written without kanji._"
+	}
+
+	if kd.Len() == 0 {
+		b.WriteString("_No KANJIDIC2 file is installed, see the README to get meanings and readings here._\n")
+	}
+
+	return b.String()
+}
+
+// renderKanjiInfo is the markdown list of a kanji's meanings, readings and figures.
+func renderKanjiInfo(k domain.Kanji) string {
+	var b strings.Builder
+	if len(k.Meanings) > 0 {
+		b.WriteString("- Meanings: " + strings.Join(k.Meanings, ", ") + "\n")
+	}
+	if len(k.On) > 0 {
+		b.WriteString("- On: " + strings.Join(k.On, "、") + "\n")
+	}
+	if len(k.Kun) > 0 {
+		b.WriteString("- Kun: " + strings.Join(k.Kun, "、") + "\n")
+	}
+
+	var figures []string
+	if k.Strokes > 0 {
+		figures = append(figures, fmt.Sprintf("%d strokes", k.Strokes))
+	}
+	if k.Grade > 0 {
+		figures = append(figures, kanjiGrade(k.Grade))
+	}
+	if k.JLPT > 0 {
+		figures = append(figures, fmt.Sprintf("old JLPT %d", k.JLPT))
+	}
+	if k.Freq > 0 {
+		figures = append(figures, fmt.Sprintf("frequency #%d", k.Freq))
+	}
+	if len(figures) > 0 {
+		b.WriteString("- " + strings.Join(figures, " · ") + "\n")
+	}
+
+	return b.String()
+}
+
+// kanjiGrade names a KANJIDIC2 grade.
+func kanjiGrade(grade int) string {
+	switch {
+	case grade <= 6:
+		return fmt.Sprintf("grade %d", grade)
+	case grade == 8:
+		return "jōyō"
+	default:
+		return "jinmeiyō"
+	}
+}
+
+// RenderExamples lists example sentences of the word with their translation, as markdown.
+// selected is marked like the selected sense, installed tells an empty corpus apart from a word
+// without examples. Japanese has no spaces for glamour to wrap at, so the sentences are wrapped
+// to width beforehand.
+func RenderExamples(word string, examples []domain.Example, selected int, installed bool, width int) string {
+	if !installed {
+		return "_No Tatoeba corpus is installed, see the README to get example sentences here._"
+	}
+
+	if len(examples) == 0 {
+		return "_No example sentences for " + word + "._"
+	}
+
+	var b strings.Builder
+	for i, ex := range examples {
+		number := strconv.Itoa(i+1) + ". "
+		indent := strings.Repeat(" ", len(number))
+
+		japanese := ex.Japanese
+		if i == selected {
+			japanese = "▶ " + japanese
+		}
+
+		form := word
+		if ex.Form != "" {
+			form = ex.Form
+		}
+
+		// room for the list marker glamour draws in front of the first line
+		lines := strings.Split(ansi.Hardwrap(japanese, max(width-3*len(number), 2), true), "\n")
+		for j, line := range lines {
+			lines[j] = strings.ReplaceAll(line, form, "**"+form+"**")
 		}
+
+		// trailing double spaces are markdown line breaks
+		b.WriteString(number + strings.Join(lines, "  \n"+indent) + "  \n")
+		b.WriteString(indent + "_" + ex.English + "_\n\n")
 	}
 
 	return b.String()
 }
 
-func renderSensePlain(eng, pos []string) string {
+// RenderRelated lists the cross-references and antonyms of every sense, as markdown.
+func RenderRelated(entry *domain.Information) string {
 	var b strings.Builder
-	if len(pos) > 0 {
-		b.WriteString("_(" + strings.Join(pos, ", ") + ")_\n")
+	for i, sense := range entry.Senses {
+		if len(sense.SeeAlso) == 0 && len(sense.Antonyms) == 0 {
+			continue
+		}
+
+		b.WriteString(fmt.Sprintf("**%d.** %s\n\n", i+1, strings.Join(sense.EnglishDefinitions, "; ")))
+		if len(sense.SeeAlso) > 0 {
+			b.WriteString("- See also: " + strings.Join(sense.SeeAlso, ", ") + "\n")
+		}
+		if len(sense.Antonyms) > 0 {
+			b.WriteString("- Antonyms: " + strings.Join(sense.Antonyms, ", ") + "\n")
+		}
+		b.WriteString("\n")
 	}
 
-	for _, def := range eng {
-		b.WriteString("- " + def + "\n")
+	if b.Len() == 0 {
+		return "_No related words._"
 	}
-	b.WriteString("\n")
+
 	return b.String()
 }
+
+// RenderResult is the two lines of a search result: the numbered headword with its reading,
+// common marker and JLPT badges, then the parts of speech and first gloss of the first sense.
+// Both are cut to width.
+func RenderResult(index int, entry *domain.Information, selected bool, width int) string {
+	head := renderHeadword(entry)
+
+	var gloss string
+	if len(entry.Senses) > 0 {
+		sense := entry.Senses[0]
+		if len(sense.PartsOfSpeech) > 0 {
+			gloss = MutedStyle.Render(strings.Join(sense.PartsOfSpeech, ", ")) + " · "
+		}
+		if len(sense.EnglishDefinitions) > 0 {
+			gloss += sense.EnglishDefinitions[0]
+		}
+	}
+
+	number := strconv.Itoa(index+1) + ". "
+	room := max(width-PaddingLeftTwo-len(number), 0)
+	indent := strings.Repeat(" ", PaddingLeftTwo+len(number))
+
+	lead := indent[:PaddingLeftTwo]
+	if selected {
+		lead = indent[:PaddingLeftOne] + HighlightStyle.PaddingLeft(0).Render("> ")
+		number = HighlightStyle.PaddingLeft(0).Render(number)
+	}
+
+	return lead + number + ansi.Truncate(head, room, "…") + "\n" + indent + ansi.Truncate(gloss, room, "…")
+}
+
+// RenderFacets is the title of the search results: the query, how many results are shown and
+// the facets narrowing them down.
+func RenderFacets(query string, shown, total int, f domain.ResultFilter, order domain.ResultOrder) string {
+	parts := []string{query}
+	if shown != total {
+		parts = append(parts, fmt.Sprintf("%d of %d", shown, total))
+	}
+
+	if f.CommonOnly {
+		parts = append(parts, "common")
+	}
+	if f.JLPT != "" {
+		parts = append(parts, strings.ToUpper(strings.TrimPrefix(f.JLPT, "jlpt-")))
+	}
+	if f.PartOfSpeech != "" {
+		parts = append(parts, f.PartOfSpeech)
+	}
+	if f.HasKanji {
+		parts = append(parts, "kanji")
+	}
+	if order != domain.OrderRelevance {
+		parts = append(parts, "by "+order.String())
+	}
+
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,84 @@
+package view
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"strings"
+)
+
+// GridCellWidth is the room a cell of RenderGrid takes, a full-width character and a space.
+const GridCellWidth = 3
+
+// GridCell is a cell of RenderGrid: a character, or a muted label when Label is set.
+type GridCell struct {
+	Char     rune
+	Label    string
+	Selected bool
+	Disabled bool
+	Cursor   bool
+}
+
+// RenderGrid lays the cells out in rows of columns, and shows rows of them from row first.
+func RenderGrid(cells []GridCell, columns, first, rows int) string {
+	lines := make([]string, 0, rows)
+	for r := first; r < first+rows && r*columns < len(cells); r++ {
+		var b strings.Builder
+		for _, c := range cells[r*columns : min((r+1)*columns, len(cells))] {
+			b.WriteString(renderCell(c) + " ")
+		}
+
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderCell(c GridCell) string {
+	if c.Label != "" {
+		return MutedStyleBold.Render(fmt.Sprintf("%2s", c.Label))
+	}
+
+	text := string(c.Char)
+	if lipgloss.Width(text) < 2 {
+		text += " "
+	}
+
+	style := lipgloss.NewStyle()
+	switch {
+	case c.Cursor:
+		style = CursorCellStyle
+	case c.Disabled:
+		style = MutedStyle
+	}
+
+	return style.Reverse(c.Selected).Render(text)
+}
+
+// RenderKanjiEntry is the kanji detail page, as markdown: the KANJIDIC2 data and the radicals
+// the kanji is made of.
+func RenderKanjiEntry(r rune, k domain.Kanji, known bool, components []rune) string {
+	var b strings.Builder
+	b.WriteString("# " + string(r) + "\n\n")
+	if known {
+		b.WriteString(renderKanjiInfo(k))
+		if len(k.Nanori) > 0 {
+			b.WriteString("- Name readings: " + strings.Join(k.Nanori, "、") + "\n")
+		}
+		if k.Radical > 0 {
+			b.WriteString(fmt.Sprintf("- Radical #%d\n", k.Radical))
+		}
+	} else {
+		b.WriteString("_Not in KANJIDIC2._\n")
+	}
+
+	if len(components) > 0 {
+		parts := make([]string, len(components))
+		for i, c := range components {
+			parts[i] = string(c)
+		}
+		b.WriteString("- Components: " + strings.Join(parts, " ") + "\n")
+	}
+
+	return b.String()
+}
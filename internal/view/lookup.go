@@ -0,0 +1,59 @@
+package view
+
+import (
+	"fmt"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"strings"
+)
+
+// LookupWidth is the width of the quick lookup box content.
+const LookupWidth = 52
+
+// lookupSenses is how many senses the quick lookup summarizes.
+const lookupSenses = 3
+
+// RenderLookup summarizes an entry in a few lines: the headword with its reading and tags,
+// then the first senses with their glosses on one line each.
+func RenderLookup(entry *domain.Information, others int) string {
+	var b strings.Builder
+
+	head := entry.Slug
+	if len(entry.Japanese) > 0 {
+		j := entry.Japanese[0]
+		head = j.Reading
+		if j.Word != "" {
+			head = j.Word + " 【" + j.Reading + "】"
+		}
+	}
+	b.WriteString(WordStyleBold.Render(head))
+
+	var tags []string
+	if entry.IsCommon {
+		tags = append(tags, "common")
+	}
+	tags = append(tags, entry.JLPT...)
+	if len(tags) > 0 {
+		b.WriteString(" " + MutedStyle.Render(strings.Join(tags, " · ")))
+	}
+	b.WriteString("\n")
+
+	for i, sense := range entry.Senses {
+		if i == lookupSenses {
+			b.WriteString(MutedStyle.Render(fmt.Sprintf("\n+%d more senses", len(entry.Senses)-i)))
+			break
+		}
+
+		line := fmt.Sprintf("%d. %s", i+1, strings.Join(sense.EnglishDefinitions, "; "))
+		if len(sense.PartsOfSpeech) > 0 {
+			line += MutedStyle.Render(" (" + strings.Join(sense.PartsOfSpeech, ", ") + ")")
+		}
+		b.WriteString("\n" + ansi.Truncate(line, LookupWidth, "…"))
+	}
+
+	if others > 0 {
+		b.WriteString("\n\n" + MutedStyle.Render(fmt.Sprintf("%d more in the search results", others)))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package view
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"strings"
+)
+
+// PlaceOverlay draws fg over bg with its top left corner at column x and row y, bg stays
+// visible around it.
+func PlaceOverlay(x, y int, fg, bg string) string {
+	bgLines := strings.Split(bg, "\n")
+	fgLines := strings.Split(fg, "\n")
+
+	for i, line := range fgLines {
+		row := y + i
+		if row < 0 {
+			continue
+		}
+
+		for len(bgLines) <= row {
+			bgLines = append(bgLines, "")
+		}
+
+		under := bgLines[row]
+		if w := ansi.StringWidth(under); w < x {
+			under += strings.Repeat(" ", x-w)
+		}
+
+		left := ansi.Truncate(under, x, "")
+		right := ansi.TruncateLeft(under, x+ansi.StringWidth(line), "")
+		bgLines[row] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+
+	return strings.Join(bgLines, "\n")
+}
+
+// CenterOverlay draws fg in the middle of a width x height bg.
+func CenterOverlay(fg, bg string, width, height int) string {
+	x := max((width-lipgloss.Width(fg))/2, 0)
+	y := max((height-lipgloss.Height(fg))/2, 0)
+
+	return PlaceOverlay(x, y, fg, bg)
+}
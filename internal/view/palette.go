@@ -0,0 +1,41 @@
+package view
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"slices"
+	"strings"
+)
+
+// PaletteItem is a command of the palette, Matched are the byte offsets the query matched.
+type PaletteItem struct {
+	Title   string
+	Matched []int
+}
+
+// RenderPalette lists the items under the query input, matched characters in bold.
+func RenderPalette(input string, items []PaletteItem, selected int) string {
+	box := lipgloss.NewStyle().Width(PaletteWidth)
+	if len(items) == 0 {
+		return box.Render(input + "\n\n" + MutedStyle.Render("No matching command"))
+	}
+
+	lines := make([]string, len(items))
+	for i, item := range items {
+		var b strings.Builder
+		for j, r := range item.Title {
+			if slices.Contains(item.Matched, j) {
+				b.WriteString(WordStyleBold.Render(string(r)))
+			} else {
+				b.WriteString(string(r))
+			}
+		}
+
+		if i == selected {
+			lines[i] = HighlightStyle.PaddingLeft(0).Render("> ") + b.String()
+		} else {
+			lines[i] = "  " + b.String()
+		}
+	}
+
+	return box.Render(input + "\n\n" + strings.Join(lines, "\n"))
+}
@@ -0,0 +1,48 @@
+package view
+
+import (
+	"fmt"
+	"github.com/ziliscite/dictionary-cli/internal/domain"
+	"strings"
+)
+
+// downstepMark follows the mora the pitch drops after.
+const downstepMark = "ꜜ"
+
+// RenderPitch draws the reading with its high morae overlined and a downstep mark where the
+// pitch drops, followed by the pattern name.
+func RenderPitch(a domain.PitchAccent) string {
+	var b strings.Builder
+	for i, m := range domain.Morae(a.Reading) {
+		if a.High(i) {
+			b.WriteString(ColorProfile().String(m).Overline().String())
+		} else {
+			b.WriteString(m)
+		}
+
+		if i+1 == a.Downstep {
+			b.WriteString(downstepMark)
+		}
+	}
+
+	return b.String() + " " + MutedStyle.Render(a.Pattern().String())
+}
+
+// pitchMarkdown writes the accents as the reading with its downstep mark, the drop position
+// and the pattern name, e.g. はꜜし [1] atamadaka. Markdown has no overline.
+func pitchMarkdown(accents []domain.PitchAccent) string {
+	parts := make([]string, len(accents))
+	for i, a := range accents {
+		var b strings.Builder
+		for j, m := range domain.Morae(a.Reading) {
+			b.WriteString(m)
+			if j+1 == a.Downstep {
+				b.WriteString(downstepMark)
+			}
+		}
+
+		parts[i] = fmt.Sprintf("%s [%d] _%s_", b.String(), a.Downstep, a.Pattern())
+	}
+
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,24 @@
+package view
+
+import "strings"
+
+// MarkMatches highlights the runs of n bytes starting at starts in a plain line, the one at
+// current stands out. Any styling the line had is dropped.
+func MarkMatches(line string, starts []int, n, current int) string {
+	var b strings.Builder
+
+	last := 0
+	for i, start := range starts {
+		style := MatchStyle
+		if i == current {
+			style = CurrentMatchStyle
+		}
+
+		b.WriteString(line[last:start])
+		b.WriteString(style.Render(line[start : start+n]))
+		last = start + n
+	}
+	b.WriteString(line[last:])
+
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package view
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Theme is the palette every style is derived from. Colors are anything lipgloss.Color accepts,
+// ANSI 256 numbers or hex values; hex values degrade when the terminal has no truecolor.
+type Theme struct {
+	Name      string `json:"name"`
+	Dark      bool   `json:"dark"`
+	Main      string `json:"main"`
+	Muted     string `json:"muted"`
+	Highlight string `json:"highlight"`
+	Spinner   string `json:"spinner"`
+	Dot       string `json:"dot"`
+	Border    string `json:"border"`
+}
+
+var builtinThemes = []Theme{
+	{Name: "dark", Dark: true, Main: "252", Muted: "240", Highlight: "170", Spinner: "70", Dot: "99", Border: "62"},
+	{Name: "light", Dark: false, Main: "235", Muted: "245", Highlight: "161", Spinner: "28", Dot: "57", Border: "61"},
+	{Name: "high-contrast", Dark: true, Main: "15", Muted: "7", Highlight: "11", Spinner: "10", Dot: "14", Border: "15"},
+}
+
+var active Theme
+
+func init() {
+	ApplyTheme(builtinThemes[0])
+}
+
+// ApplyTheme makes t the active theme and rebuilds every style from it.
+func ApplyTheme(t Theme) {
+	active = t
+	buildStyles(t)
+}
+
+func ActiveTheme() Theme {
+	return active
+}
+
+func BuiltinThemes() []string {
+	names := make([]string, len(builtinThemes))
+	for i, t := range builtinThemes {
+		names[i] = t.Name
+	}
+
+	return names
+}
+
+// NextTheme is the built-in theme after the active one, wrapping around.
+func NextTheme() Theme {
+	for i, t := range builtinThemes {
+		if t.Name == active.Name {
+			return builtinThemes[(i+1)%len(builtinThemes)]
+		}
+	}
+
+	return builtinThemes[0]
+}
+
+// LoadTheme reads a user theme file. Missing colors are taken from the built-in theme
+// with the same darkness.
+func LoadTheme(path string) (Theme, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("read theme: %w", err)
+	}
+
+	var t Theme
+	if err = json.Unmarshal(b, &t); err != nil {
+		return Theme{}, fmt.Errorf("decode theme %s: %w", path, err)
+	}
+
+	base := builtinThemes[1]
+	if t.Dark {
+		base = builtinThemes[0]
+	}
+
+	for _, c := range []struct {
+		dst *string
+		src string
+	}{
+		{&t.Main, base.Main},
+		{&t.Muted, base.Muted},
+		{&t.Highlight, base.Highlight},
+		{&t.Spinner, base.Spinner},
+		{&t.Dot, base.Dot},
+		{&t.Border, base.Border},
+	} {
+		if *c.dst == "" {
+			*c.dst = c.src
+		}
+	}
+
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return t, nil
+}
+
+// ResolveTheme finds the theme named by spec: a built-in theme, a path to a theme file, or
+// the name of a file in the themes directory of the user config. An empty spec picks dark
+// or light from the terminal background.
+func ResolveTheme(spec string) (Theme, error) {
+	if spec == "" {
+		if lipgloss.HasDarkBackground() {
+			return builtinThemes[0], nil
+		}
+
+		return builtinThemes[1], nil
+	}
+
+	for _, t := range builtinThemes {
+		if t.Name == spec {
+			return t, nil
+		}
+	}
+
+	if _, err := os.Stat(spec); err == nil {
+		return LoadTheme(spec)
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return Theme{}, err
+	}
+
+	path := filepath.Join(dir, "dictionary-cli", "themes", spec+".json")
+	if _, err = os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return Theme{}, fmt.Errorf("unknown theme %q, built-in themes are %s", spec, strings.Join(BuiltinThemes(), ", "))
+	}
+
+	return LoadTheme(path)
+}
+
+// ColorProfile is what the terminal supports; NO_COLOR and truecolor are detected from the environment.
+func ColorProfile() termenv.Profile {
+	return lipgloss.ColorProfile()
+}
+
+// GlamourStyle derives the markdown style from the active theme, so rendered entries
+// match the rest of the interface.
+func GlamourStyle() ansi.StyleConfig {
+	if ColorProfile() == termenv.Ascii {
+		return styles.NoTTYStyleConfig
+	}
+
+	s := styles.LightStyleConfig
+	if active.Dark {
+		s = styles.DarkStyleConfig
+	}
+
+	main, muted, highlight, dot := active.Main, active.Muted, active.Highlight, active.Dot
+
+	s.Document.Color = &main
+	s.Text.Color = &main
+	s.Paragraph.Color = &main
+	s.Heading.Color = &highlight
+	s.H1.Color = &highlight
+	s.H1.BackgroundColor = nil
+	s.Strong.Color = &highlight
+	s.Emph.Color = &muted
+	s.Item.Color = &dot
+	s.Enumeration.Color = &dot
+	s.HorizontalRule.Color = &muted
+	s.Link.Color = &dot
+	s.LinkText.Color = &highlight
+
+	return s
+}